@@ -21,6 +21,20 @@ type PluginConfig struct {
 	InterfaceBlock int      `json:"interfaceBlock"`
 	ExcludeCIDRs   []string `json:"excludeCIDRs,omitempty"`
 	VlanMode       string   `json:"vlanMode,omitempty"`
+	// OverlayVNI is base VNI of overlay devices in vxlan vlanMode, VNI of interface index i is OverlayVNI+i (default: 4096)
+	OverlayVNI int `json:"overlayVNI,omitempty"`
+	// OverlayPort is UDP port of overlay devices in vxlan vlanMode (default: 4789)
+	OverlayPort int `json:"overlayPort,omitempty"`
+	// RouteMode is static (default) to push host routes to every daemon or bgp to let each daemon announce its own pod CIDRs
+	// +kubebuilder:validation:Enum=static;bgp
+	RouteMode string `json:"routeMode,omitempty"`
+	// Multipath programs one weighted multipath route per destination pod CIDR over all shared interfaces in L3 mode
+	Multipath bool `json:"multipath,omitempty"`
+	// MultipathWeights maps master network address to next-hop weight (default: 1)
+	MultipathWeights map[string]int `json:"multipathWeights,omitempty"`
+	// SourceRouting adds a table and source rule per interface index keyed on its VLAN CIDR in L3 mode,
+	// so that traffic from pod interface of the index leaves via the master of the same index
+	SourceRouting bool `json:"sourceRouting,omitempty"`
 }
 
 type HostInterfaceInfo struct {
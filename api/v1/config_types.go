@@ -25,6 +25,29 @@ type DaemonSpec struct {
 	DaemonPort      int                         `json:"port"`
 	Resources       corev1.ResourceRequirements `json:"resources,omitempty"`
 	Tolerations     []corev1.Toleration         `json:"tolerations,omitempty" protobuf:"bytes,22,opt,name=tolerations"`
+	// InterfaceFilter limits interfaces discovered and reported by daemon as masters
+	InterfaceFilter *InterfaceFilter `json:"interfaceFilter,omitempty"`
+}
+
+// InterfaceFilter selects interfaces of the host that can become masters
+type InterfaceFilter struct {
+	// Include keeps only interfaces matching any of the selectors, all interfaces if empty
+	Include []InterfaceSelector `json:"include,omitempty"`
+	// Exclude removes interfaces matching any of the selectors
+	Exclude []InterfaceSelector `json:"exclude,omitempty"`
+}
+
+// InterfaceSelector matches interface if all of its set fields match, a field matches any of its values
+type InterfaceSelector struct {
+	// Names are interface name globs such as eth* or ens[1-2]f0
+	Names        []string `json:"names,omitempty"`
+	PciAddresses []string `json:"pciAddresses,omitempty"`
+	// Vendors and Products are PCI IDs such as 15b3 and 101d
+	Vendors  []string `json:"vendors,omitempty"`
+	Products []string `json:"products,omitempty"`
+	Drivers  []string `json:"drivers,omitempty"`
+	// Subnets match interface with IP in any of the CIDRs
+	Subnets []string `json:"subnets,omitempty"`
 }
 
 type HostPathMount struct {
@@ -49,6 +72,8 @@ type ConfigSpec struct {
 	LongReconcileMinutes   int        `json:"longReconcileMinutes,omitempty"`
 	ContextTimeoutMinutes  int        `json:"contextTimeoutMinutes,omitempty"`
 	LogLevel               int        `json:"logLevel,omitempty"`
+	// RouteRollout bounds route updates of all networks without their own routeRollout
+	RouteRollout *RolloutPolicy `json:"routeRollout,omitempty"`
 }
 
 // ConfigStatus defines the observed state of Config
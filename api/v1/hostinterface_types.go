@@ -16,12 +16,53 @@ type InterfaceInfoType struct {
 	Vendor        string `json:"vendor,omitempty"`
 	Product       string `json:"product,omitempty"`
 	PciAddress    string `json:"pciAddress,omitempty"`
+	// Speed is link speed in Mbps, zero if unknown
+	Speed           int    `json:"speed,omitempty"`
+	MTU             int    `json:"mtu,omitempty"`
+	OperState       string `json:"operState,omitempty"`
+	Carrier         bool   `json:"carrier,omitempty"`
+	Driver          string `json:"driver,omitempty"`
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// NumaNode is NUMA node of PCI device, empty if not applicable
+	NumaNode      string `json:"numaNode,omitempty"`
+	SriovTotalVFs int    `json:"sriovTotalVFs,omitempty"`
+	SriovNumVFs   int    `json:"sriovNumVFs,omitempty"`
+	RdmaDevice    string `json:"rdmaDevice,omitempty"`
+	// SecondaryAddresses are IPv4 addresses other than NetAddress and HostIP, matched against masterNets
+	SecondaryAddresses []InterfaceAddress `json:"secondaryAddresses,omitempty"`
+	// Slaves are slave interfaces if interface is bond or team master
+	Slaves []SlaveInfo `json:"slaves,omitempty"`
+}
+
+// SlaveInfo is a slave interface of bond or team master
+type SlaveInfo struct {
+	InterfaceName string `json:"interfaceName"`
+	PciAddress    string `json:"pciAddress,omitempty"`
+}
+
+// InterfaceAddress is an IPv4 address of interface with its network address
+type InterfaceAddress struct {
+	NetAddress string `json:"netAddress"`
+	HostIP     string `json:"hostIP"`
 }
 
 func (i InterfaceInfoType) Equal(cmp InterfaceInfoType) bool {
+	if len(i.SecondaryAddresses) != len(cmp.SecondaryAddresses) {
+		return false
+	}
+	for index, addr := range i.SecondaryAddresses {
+		if addr != cmp.SecondaryAddresses[index] {
+			return false
+		}
+	}
 	return i.InterfaceName == cmp.InterfaceName && i.NetAddress == cmp.NetAddress && i.HostIP == cmp.HostIP
 }
 
+// GetAddresses returns the first and secondary addresses of interface
+func (i InterfaceInfoType) GetAddresses() []InterfaceAddress {
+	return append([]InterfaceAddress{{NetAddress: i.NetAddress, HostIP: i.HostIP}}, i.SecondaryAddresses...)
+}
+
 // HostInterfaceSpec defines the desired state of HostInterface
 type HostInterfaceSpec struct {
 	HostName   string              `json:"hostName"`
@@ -18,6 +18,7 @@ import (
 // IPAM is ipam specification
 // MainPlugin is plugin specification
 // Policy is general policy of the pool
+// IfNameTemplate is pod interface name template, default: {ifname}-{index}
 type MultiNicNetworkSpec struct {
 	MasterNetAddrs []string         `json:"masterNets,omitempty"`
 	Subnet         string           `json:"subnet,omitempty"`
@@ -26,15 +27,50 @@ type MultiNicNetworkSpec struct {
 	MainPlugin     PluginSpec       `json:"plugin"`
 	Policy         AttachmentPolicy `json:"attachPolicy,omitempty"`
 	Namespaces     []string         `json:"namespaces,omitempty"`
+	// IfNameTemplate supports {ifname}, {index}, {master}, {netIndex} (position in masterNets),
+	// and {interfaceIndex} (CIDR interface index, multi-NIC IPAM only), e.g., rail{netIndex}
+	// +kubebuilder:validation:MaxLength=63
+	IfNameTemplate string `json:"ifNameTemplate,omitempty"`
+	// RouteRollout bounds route updates of this network, overriding routeRollout of Config
+	RouteRollout *RolloutPolicy `json:"routeRollout,omitempty"`
+}
+
+// RolloutPolicy bounds how route updates are pushed to the daemons
+type RolloutPolicy struct {
+	// MaxConcurrent is the number of nodes updated at the same time (default: 1)
+	// +kubebuilder:validation:Minimum=0
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	// CanaryPercent is the percentage of nodes updated first, the rest is updated only if all canary nodes succeed
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CanaryPercent int `json:"canaryPercent,omitempty"`
+	// MaxFailurePercent halts the rollout when failed nodes exceed the percentage of updated nodes (default: never halt)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxFailurePercent *int `json:"maxFailurePercent,omitempty"`
 }
 
 // reference: github.com/containernetworking/cni/pkg/types
+// MTU, Mode, Flag and Sysctls are typed settings of macvlan and ipvlan, overriding args
 type PluginSpec struct {
 	CNIVersion   string            `json:"cniVersion"`
 	Type         string            `json:"type"`
 	Capabilities map[string]bool   `json:"capabilities,omitempty"`
 	DNS          DNS               `json:"dns,omitempty"`
 	CNIArgs      map[string]string `json:"args,omitempty"`
+	// MTU of pod interface, adapted to master MTU if master MTU is smaller
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	MTU int `json:"mtu,omitempty"`
+	// Mode is bridge, private, vepa, or passthru for macvlan and l2, l3, or l3s for ipvlan
+	// +kubebuilder:validation:Enum=bridge;private;vepa;passthru;l2;l3;l3s
+	Mode string `json:"mode,omitempty"`
+	// Flag is bridge, private, or vepa for ipvlan
+	// +kubebuilder:validation:Enum=bridge;private;vepa
+	Flag string `json:"flag,omitempty"`
+	// Sysctls are set on each pod interface, IFNAME in the key is replaced by the interface name
+	// (e.g., net.ipv4.conf.IFNAME.arp_ignore)
+	Sysctls map[string]string `json:"sysctls,omitempty"`
 }
 
 // reference: github.com/containernetworking/cni/pkg/types
@@ -49,9 +85,27 @@ type DNS struct {
 // Strategy is one of None, CostOpt, PerfOpt, QoSClass
 // Target is target bandwidth in a format (d+)Gbps, (d+)Mbps, (d+)Kbps
 // required for CostOpt and PerfOpt
+// Filters and Scores compose a selection pipeline that overrides Strategy if set
 type AttachmentPolicy struct {
 	Strategy string `json:"strategy"`
 	Target   string `json:"target,omitempty"`
+	// Mode is how none and devClass strategies pick NICs if fewer than available are requested:
+	// ordered (default) by network address or leastAttached by the fewest pods attached on the host
+	// +kubebuilder:validation:Enum=ordered;leastAttached
+	Mode string `json:"mode,omitempty"`
+	// Filters are filter plugins applied in order: devClass, masters, health
+	Filters []string `json:"filters,omitempty"`
+	// Scores are score plugins summed by weight: numa, load, cost
+	Scores []ScorePluginSpec `json:"scores,omitempty"`
+}
+
+// ScorePluginSpec is a score plugin with its weight
+type ScorePluginSpec struct {
+	// +kubebuilder:validation:Enum=numa;load;cost
+	Name string `json:"name"`
+	// Weight multiplies score from 0 to 100 of the plugin (default: 1)
+	// +kubebuilder:validation:Minimum=0
+	Weight int `json:"weight,omitempty"`
 }
 
 // +enum
@@ -99,6 +153,38 @@ type DiscoverStatus struct {
 	CIDRProcessedHost      int `json:"cidrProcessed"`
 }
 
+// PlannedChange is a change of VLAN CIDR, host block, IPPool, or route in a plan
+type PlannedChange struct {
+	// Action is add, delete, or change
+	Action string `json:"action"`
+	// Name is master network address, host name and network address, IPPool name, or route destination
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// CIDRPlan is a preview of changes from the updated IPAM config of the network, applied by setting its ID
+// to the multinic.fms.io/apply-plan annotation
+type CIDRPlan struct {
+	ID           string          `json:"id"`
+	VlanCIDRs    []PlannedChange `json:"vlanCIDRs,omitempty"`
+	HostBlocks   []PlannedChange `json:"hostBlocks,omitempty"`
+	IPPools      []PlannedChange `json:"ippools,omitempty"`
+	Routes       []PlannedChange `json:"routes,omitempty"`
+	AffectedPods []string        `json:"affectedPods,omitempty"`
+	CreatedTime  metav1.Time     `json:"createdTime"`
+}
+
+// RolloutStatus is progress of the last route rollout of the network
+type RolloutStatus struct {
+	Total       int      `json:"total"`
+	Updated     int      `json:"updated"`
+	Failed      int      `json:"failed"`
+	Halted      bool     `json:"halted,omitempty"`
+	FailedHosts []string `json:"failedHosts,omitempty"`
+	Message     string   `json:"message,omitempty"`
+}
+
 // MultiNicNetworkStatus defines the observed state of MultiNicNetwork
 type MultiNicNetworkStatus struct {
 	ComputeResults  []NicNetworkResult `json:"computeResults"`
@@ -107,6 +193,10 @@ type MultiNicNetworkStatus struct {
 	RouteStatus     `json:"routeStatus"`
 	Message         string      `json:"message"`
 	LastSyncTime    metav1.Time `json:"lastSyncTime"`
+	// Plan is a pending change of IPAM config not applied yet
+	Plan *CIDRPlan `json:"plan,omitempty"`
+	// Rollout is progress of route updates when rollout policy is set
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -32,6 +32,16 @@ func (in *Allocation) DeepCopy() *Allocation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AttachmentPolicy) DeepCopyInto(out *AttachmentPolicy) {
 	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scores != nil {
+		in, out := &in.Scores, &out.Scores
+		*out = make([]ScorePluginSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachmentPolicy.
@@ -123,6 +133,47 @@ func (in *CIDRList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRPlan) DeepCopyInto(out *CIDRPlan) {
+	*out = *in
+	if in.VlanCIDRs != nil {
+		in, out := &in.VlanCIDRs, &out.VlanCIDRs
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostBlocks != nil {
+		in, out := &in.HostBlocks, &out.HostBlocks
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPPools != nil {
+		in, out := &in.IPPools, &out.IPPools
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.AffectedPods != nil {
+		in, out := &in.AffectedPods, &out.AffectedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.CreatedTime.DeepCopyInto(&out.CreatedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRPlan.
+func (in *CIDRPlan) DeepCopy() *CIDRPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CIDRSpec) DeepCopyInto(out *CIDRSpec) {
 	*out = *in
@@ -224,6 +275,11 @@ func (in *ConfigList) DeepCopyObject() runtime.Object {
 func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 	*out = *in
 	in.Daemon.DeepCopyInto(&out.Daemon)
+	if in.RouteRollout != nil {
+		in, out := &in.RouteRollout, &out.RouteRollout
+		*out = new(RolloutPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSpec.
@@ -323,6 +379,11 @@ func (in *DaemonSpec) DeepCopyInto(out *DaemonSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InterfaceFilter != nil {
+		in, out := &in.InterfaceFilter, &out.InterfaceFilter
+		*out = new(InterfaceFilter)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSpec.
@@ -546,7 +607,9 @@ func (in *HostInterfaceSpec) DeepCopyInto(out *HostInterfaceSpec) {
 	if in.Interfaces != nil {
 		in, out := &in.Interfaces, &out.Interfaces
 		*out = make([]InterfaceInfoType, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -690,9 +753,63 @@ func (in *IPPoolStatus) DeepCopy() *IPPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceAddress) DeepCopyInto(out *InterfaceAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceAddress.
+func (in *InterfaceAddress) DeepCopy() *InterfaceAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceFilter) DeepCopyInto(out *InterfaceFilter) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]InterfaceSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]InterfaceSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceFilter.
+func (in *InterfaceFilter) DeepCopy() *InterfaceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterfaceInfoType) DeepCopyInto(out *InterfaceInfoType) {
 	*out = *in
+	if in.SecondaryAddresses != nil {
+		in, out := &in.SecondaryAddresses, &out.SecondaryAddresses
+		*out = make([]InterfaceAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.Slaves != nil {
+		in, out := &in.Slaves, &out.Slaves
+		*out = make([]SlaveInfo, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceInfoType.
@@ -705,6 +822,51 @@ func (in *InterfaceInfoType) DeepCopy() *InterfaceInfoType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceSelector) DeepCopyInto(out *InterfaceSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PciAddresses != nil {
+		in, out := &in.PciAddresses, &out.PciAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Vendors != nil {
+		in, out := &in.Vendors, &out.Vendors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSelector.
+func (in *InterfaceSelector) DeepCopy() *InterfaceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinkStat) DeepCopyInto(out *LinkStat) {
 	*out = *in
@@ -788,12 +950,17 @@ func (in *MultiNicNetworkSpec) DeepCopyInto(out *MultiNicNetworkSpec) {
 		copy(*out, *in)
 	}
 	in.MainPlugin.DeepCopyInto(&out.MainPlugin)
-	out.Policy = in.Policy
+	in.Policy.DeepCopyInto(&out.Policy)
 	if in.Namespaces != nil {
 		in, out := &in.Namespaces, &out.Namespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RouteRollout != nil {
+		in, out := &in.RouteRollout, &out.RouteRollout
+		*out = new(RolloutPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNicNetworkSpec.
@@ -816,6 +983,16 @@ func (in *MultiNicNetworkStatus) DeepCopyInto(out *MultiNicNetworkStatus) {
 	}
 	out.DiscoverStatus = in.DiscoverStatus
 	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(CIDRPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNicNetworkStatus.
@@ -843,6 +1020,21 @@ func (in *NicNetworkResult) DeepCopy() *NicNetworkResult {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginConfig) DeepCopyInto(out *PluginConfig) {
 	*out = *in
@@ -856,6 +1048,13 @@ func (in *PluginConfig) DeepCopyInto(out *PluginConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MultipathWeights != nil {
+		in, out := &in.MultipathWeights, &out.MultipathWeights
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfig.
@@ -886,6 +1085,13 @@ func (in *PluginSpec) DeepCopyInto(out *PluginSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginSpec.
@@ -897,3 +1103,73 @@ func (in *PluginSpec) DeepCopy() *PluginSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPolicy) DeepCopyInto(out *RolloutPolicy) {
+	*out = *in
+	if in.MaxFailurePercent != nil {
+		in, out := &in.MaxFailurePercent, &out.MaxFailurePercent
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPolicy.
+func (in *RolloutPolicy) DeepCopy() *RolloutPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.FailedHosts != nil {
+		in, out := &in.FailedHosts, &out.FailedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScorePluginSpec) DeepCopyInto(out *ScorePluginSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScorePluginSpec.
+func (in *ScorePluginSpec) DeepCopy() *ScorePluginSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScorePluginSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlaveInfo) DeepCopyInto(out *SlaveInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlaveInfo.
+func (in *SlaveInfo) DeepCopy() *SlaveInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(SlaveInfo)
+	in.DeepCopyInto(out)
+	return out
+}
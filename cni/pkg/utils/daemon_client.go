@@ -0,0 +1,145 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/types"
+)
+
+const (
+	DEFAULT_DAEMON_TIMEOUT       = 30  // seconds
+	DEFAULT_DAEMON_RETRIES       = 3   // retries after the first attempt
+	DEFAULT_DAEMON_RETRY_BACKOFF = 500 // milliseconds
+	maxDaemonRetryBackoff        = 10 * time.Second
+)
+
+// DaemonClientConfig defines timeout and retry of the requests to multi-nic-cni daemon
+// zero value uses default, negative daemonRetries disables retry
+type DaemonClientConfig struct {
+	// per-attempt timeout in seconds
+	DaemonTimeout int `json:"daemonTimeout,omitempty"`
+	// number of retries after the first attempt
+	DaemonRetries int `json:"daemonRetries,omitempty"`
+	// initial backoff in milliseconds, doubled on every retry
+	DaemonRetryBackoff int `json:"daemonRetryBackoff,omitempty"`
+}
+
+func (c DaemonClientConfig) timeout() time.Duration {
+	if c.DaemonTimeout > 0 {
+		return time.Duration(c.DaemonTimeout) * time.Second
+	}
+	return DEFAULT_DAEMON_TIMEOUT * time.Second
+}
+
+func (c DaemonClientConfig) retries() int {
+	if c.DaemonRetries < 0 {
+		return 0
+	}
+	if c.DaemonRetries == 0 {
+		return DEFAULT_DAEMON_RETRIES
+	}
+	return c.DaemonRetries
+}
+
+func (c DaemonClientConfig) backoff(retry int) time.Duration {
+	backoff := DEFAULT_DAEMON_RETRY_BACKOFF * time.Millisecond
+	if c.DaemonRetryBackoff > 0 {
+		backoff = time.Duration(c.DaemonRetryBackoff) * time.Millisecond
+	}
+	for i := 1; i < retry && backoff < maxDaemonRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxDaemonRetryBackoff {
+		return maxDaemonRetryBackoff
+	}
+	return backoff
+}
+
+// isRetriable returns true if the request may succeed on the next attempt and was not processed by daemon,
+// that is, try-again-later error from daemon or failure to connect other than refused connection.
+// A timeout or read error after the request is sent is not retried since daemon may have processed it,
+// e.g., retrying allocate would allocate another address to the pod.
+func isRetriable(err error) bool {
+	if cniErr, ok := err.(*types.Error); ok {
+		return cniErr.Code == types.ErrTryAgainLater
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return !errors.Is(err, syscall.ECONNREFUSED)
+	}
+	return false
+}
+
+// PostDaemon posts JSON request to daemon path and returns body of OK response
+// retries with backoff on connection failure and try-again-later error from daemon,
+// fails fast if daemon port is closed
+func (c DaemonClientConfig) PostDaemon(daemonIP string, daemonPort int, path string, request interface{}) ([]byte, error) {
+	jsonReq, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal fail: %v", err)
+	}
+	address := fmt.Sprintf("http://%s:%d/%s", daemonIP, daemonPort, path)
+	client := http.Client{
+		Timeout: c.timeout(),
+	}
+	defer client.CloseIdleConnections()
+
+	maxRetries := c.retries()
+	attempts := 0
+	for retry := 0; ; retry++ {
+		if retry > 0 {
+			time.Sleep(c.backoff(retry))
+		}
+		var body []byte
+		attempts += 1
+		body, err = postOnce(client, address, path, jsonReq)
+		if err == nil {
+			return body, nil
+		}
+		if retry >= maxRetries || !isRetriable(err) {
+			break
+		}
+		if Logger != nil {
+			Logger.Debug(fmt.Sprintf("retry %s (%d/%d): %v", address, retry+1, maxRetries, err))
+		}
+	}
+	if _, ok := err.(*types.Error); ok {
+		return nil, err
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		// daemon port is closed
+		return nil, types.NewError(types.ErrTryAgainLater,
+			fmt.Sprintf("%s: daemon is not listening on %s:%d", path, daemonIP, daemonPort), err.Error())
+	}
+	return nil, types.NewError(types.ErrTryAgainLater,
+		fmt.Sprintf("%s: daemon %s:%d did not respond after %d attempt(s)", path, daemonIP, daemonPort, attempts), err.Error())
+}
+
+func postOnce(client http.Client, address, path string, jsonReq []byte) ([]byte, error) {
+	res, err := client.Post(address, "application/json; charset=utf-8", bytes.NewBuffer(jsonReq))
+	if err != nil {
+		return nil, fmt.Errorf("post fail: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, NewDaemonError(path, res)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %v", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package utils
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func getServerAddress(srv *httptest.Server) (string, int) {
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+var _ = Describe("PostDaemon", func() {
+	clientConfig := DaemonClientConfig{
+		DaemonTimeout:      1,
+		DaemonRetries:      2,
+		DaemonRetryBackoff: 10,
+	}
+
+	It("retries on try-again-later error", func() {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts += 1
+			if attempts < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"reason":"BackendUnavailable","message":"list fail"}`))
+				return
+			}
+			w.Write([]byte(`"ok"`))
+		}))
+		defer srv.Close()
+		ip, port := getServerAddress(srv)
+		body, err := clientConfig.PostDaemon(ip, port, "select", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(`"ok"`))
+		Expect(attempts).To(Equal(2))
+	})
+
+	It("does not retry on other daemon error", func() {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts += 1
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"reason":"NoInterfaceSelected","message":"none"}`))
+		}))
+		defer srv.Close()
+		ip, port := getServerAddress(srv)
+		_, err := clientConfig.PostDaemon(ip, port, "select", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*types.Error).Code).To(Equal(ErrNoInterfaceSelected))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("does not retry after request is sent", func() {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts += 1
+			time.Sleep(1500 * time.Millisecond)
+			w.Write([]byte(`"ok"`))
+		}))
+		defer srv.Close()
+		ip, port := getServerAddress(srv)
+		_, err := clientConfig.PostDaemon(ip, port, "allocate", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*types.Error).Code).To(Equal(types.ErrTryAgainLater))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("fails fast when daemon port is closed", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		ip, port := getServerAddress(srv)
+		srv.Close()
+		start := time.Now()
+		_, err := clientConfig.PostDaemon(ip, port, "select", "")
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		cniErr := err.(*types.Error)
+		Expect(cniErr.Code).To(Equal(types.ErrTryAgainLater))
+		Expect(cniErr.Msg).To(ContainSubstring("not listening"))
+	})
+
+	It("computes backoff", func() {
+		Expect(clientConfig.backoff(1)).To(Equal(10 * time.Millisecond))
+		Expect(clientConfig.backoff(3)).To(Equal(40 * time.Millisecond))
+		Expect(DaemonClientConfig{}.backoff(100)).To(Equal(maxDaemonRetryBackoff))
+		Expect(DaemonClientConfig{DaemonRetries: -1}.retries()).To(Equal(0))
+	})
+})
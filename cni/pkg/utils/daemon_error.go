@@ -0,0 +1,81 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/containernetworking/cni/pkg/types"
+)
+
+// plugin-specific CNI error codes (>= 100)
+const (
+	ErrAddressUnavailable  uint = 100
+	ErrNoInterfaceSelected uint = 101
+)
+
+// DaemonError is the body of non-OK response from multi-nic-cni daemon
+type DaemonError struct {
+	Reason    string   `json:"reason"`
+	Message   string   `json:"message"`
+	Network   string   `json:"network,omitempty"`
+	Host      string   `json:"host,omitempty"`
+	Interface string   `json:"interface,omitempty"`
+	Missing   []string `json:"missing,omitempty"`
+}
+
+var daemonReasonCodeMap = map[string]uint{
+	"InvalidRequest":      types.ErrDecodingFailure,
+	"BackendUnavailable":  types.ErrTryAgainLater,
+	"NetworkNotFound":     types.ErrInvalidNetworkConfig,
+	"IPPoolNotFound":      types.ErrTryAgainLater,
+	"AddressUnavailable":  ErrAddressUnavailable,
+	"NoInterfaceSelected": ErrNoInterfaceSelected,
+}
+
+// Code returns CNI error code corresponding to the reason
+func (e DaemonError) Code() uint {
+	if code, ok := daemonReasonCodeMap[e.Reason]; ok {
+		return code
+	}
+	return types.ErrInternal
+}
+
+// Details returns network, host, interface, and missing items in key=value format
+func (e DaemonError) Details() string {
+	details := []string{}
+	if e.Network != "" {
+		details = append(details, "network="+e.Network)
+	}
+	if e.Host != "" {
+		details = append(details, "host="+e.Host)
+	}
+	if e.Interface != "" {
+		details = append(details, "interface="+e.Interface)
+	}
+	if len(e.Missing) > 0 {
+		details = append(details, "missing="+strings.Join(e.Missing, ","))
+	}
+	return strings.Join(details, " ")
+}
+
+// NewDaemonError converts non-OK daemon response to CNI error
+func NewDaemonError(path string, res *http.Response) *types.Error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return types.NewError(types.ErrInternal, fmt.Sprintf("%s: %s", path, res.Status), err.Error())
+	}
+	daemonErr := DaemonError{}
+	if err := json.Unmarshal(body, &daemonErr); err != nil || daemonErr.Reason == "" {
+		return types.NewError(types.ErrInternal, fmt.Sprintf("%s: %s", path, res.Status), strings.TrimSpace(string(body)))
+	}
+	msg := fmt.Sprintf("%s: %s: %s", path, daemonErr.Reason, daemonErr.Message)
+	return types.NewError(daemonErr.Code(), msg, daemonErr.Details())
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package utils
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/containernetworking/cni/pkg/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func newResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+var _ = Describe("NewDaemonError", func() {
+	It("converts daemon reason to CNI error code", func() {
+		body := `{"reason":"IPPoolNotFound","message":"no IPPool","network":"net-a","host":"node1","interface":"eth1","missing":["eth1"]}`
+		err := NewDaemonError("allocate", newResponse(http.StatusNotFound, body))
+		Expect(err.Code).To(Equal(types.ErrTryAgainLater))
+		Expect(err.Msg).To(Equal("allocate: IPPoolNotFound: no IPPool"))
+		Expect(err.Details).To(Equal("network=net-a host=node1 interface=eth1 missing=eth1"))
+	})
+
+	It("uses plugin-specific code", func() {
+		body := `{"reason":"NoInterfaceSelected","message":"no interface"}`
+		err := NewDaemonError("select", newResponse(http.StatusUnprocessableEntity, body))
+		Expect(err.Code).To(Equal(ErrNoInterfaceSelected))
+		Expect(err.Details).To(BeEmpty())
+	})
+
+	It("falls back to internal error on unstructured body", func() {
+		err := NewDaemonError("select", newResponse(http.StatusInternalServerError, "oops\n"))
+		Expect(err.Code).To(Equal(types.ErrInternal))
+		Expect(err.Details).To(Equal("oops"))
+	})
+})
@@ -8,12 +8,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"time"
 
-	"bytes"
-	"errors"
+	"github.com/containernetworking/plugins/pkg/utils"
 )
 
 const (
@@ -39,7 +35,7 @@ type IPResponse struct {
 	VLANBlockSize string `json:"block"`
 }
 
-func RequestIP(daemonIP string, daemonPort int, podName string, podNamespace string, hostName string, defName string, masters []string) ([]IPResponse, error) {
+func RequestIP(daemonIP string, daemonPort int, clientConfig utils.DaemonClientConfig, podName string, podNamespace string, hostName string, defName string, masters []string) ([]IPResponse, error) {
 	var response []IPResponse
 	if daemonPort == 0 {
 		daemonPort = DEFAULT_DAEMON_PORT
@@ -47,7 +43,6 @@ func RequestIP(daemonIP string, daemonPort int, podName string, podNamespace str
 	if daemonIP == "" {
 		daemonIP = DEFAULT_DAEMON_IP
 	}
-	address := fmt.Sprintf("http://%s:%d/%s", daemonIP, daemonPort, ALLOCATE_PATH)
 	request := IPRequest{
 		PodName:          podName,
 		PodNamespace:     podNamespace,
@@ -56,42 +51,22 @@ func RequestIP(daemonIP string, daemonPort int, podName string, podNamespace str
 		InterfaceNames:   masters,
 	}
 
-	jsonReq, err := json.Marshal(request)
-
+	body, err := clientConfig.PostDaemon(daemonIP, daemonPort, ALLOCATE_PATH, request)
 	if err != nil {
-		return response, fmt.Errorf("marshal fail: %v", err)
-	} else {
-		client := http.Client{
-			Timeout: 2 * time.Minute,
-		}
-		defer client.CloseIdleConnections()
-		res, err := client.Post(address, "application/json; charset=utf-8", bytes.NewBuffer(jsonReq))
-		if err != nil {
-			return response, fmt.Errorf("post fail: %v", err)
-		}
-		defer res.Body.Close()
-		if res.StatusCode != http.StatusOK {
-			return response, errors.New(res.Status)
-		}
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return response, fmt.Errorf("read body: %v", err)
-		}
-		err = json.Unmarshal(body, &response)
-		if err == nil && len(response) == 0 {
-			return response, fmt.Errorf("response nothing")
-		}
 		return response, err
 	}
+	err = json.Unmarshal(body, &response)
+	if err == nil && len(response) == 0 {
+		return response, fmt.Errorf("response nothing")
+	}
+	return response, err
 }
 
-func Deallocate(daemonPort int, podName string, podNamespace string, hostName string, defName string) ([]IPResponse, error) {
+func Deallocate(daemonPort int, clientConfig utils.DaemonClientConfig, podName string, podNamespace string, hostName string, defName string) ([]IPResponse, error) {
 	var response []IPResponse
 	if daemonPort == 0 {
 		daemonPort = DEFAULT_DAEMON_PORT
 	}
-
-	address := fmt.Sprintf("http://localhost:%d/%s", daemonPort, DEALLOCATE_PATH)
 	request := IPRequest{
 		PodName:          podName,
 		PodNamespace:     podNamespace,
@@ -99,32 +74,13 @@ func Deallocate(daemonPort int, podName string, podNamespace string, hostName st
 		NetAttachDefName: defName,
 	}
 
-	jsonReq, err := json.Marshal(request)
-
+	body, err := clientConfig.PostDaemon(DEFAULT_DAEMON_IP, daemonPort, DEALLOCATE_PATH, request)
 	if err != nil {
-		return response, fmt.Errorf("marshal fail: %v", err)
-	} else {
-		client := http.Client{
-			Timeout: 2 * time.Minute,
-		}
-		defer client.CloseIdleConnections()
-		res, err := client.Post(address, "application/json; charset=utf-8", bytes.NewBuffer(jsonReq))
-		if err != nil {
-			return response, fmt.Errorf("post fail: %v", err)
-		}
-		defer res.Body.Close()
-		if res.StatusCode != http.StatusOK {
-			return response, errors.New(res.Status)
-		}
-
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return response, fmt.Errorf("read body: %v", err)
-		}
-		err = json.Unmarshal(body, &response)
-		if err == nil && len(response) == 0 {
-			return response, fmt.Errorf("response nothing")
-		}
 		return response, err
 	}
+	err = json.Unmarshal(body, &response)
+	if err == nil && len(response) == 0 {
+		return response, fmt.Errorf("response nothing")
+	}
+	return response, err
 }
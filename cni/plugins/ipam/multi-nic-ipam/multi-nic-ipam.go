@@ -33,6 +33,7 @@ const (
 
 type Net struct {
 	types.NetConf
+	utils.DaemonClientConfig
 	Subnet         string      `json:"subnet"`
 	MasterNetAddrs []string    `json:"masterNets"`
 	Masters        []string    `json:"masters"`
@@ -201,9 +202,13 @@ func cmdAdd(args *skel.CmdArgs) error {
 		}
 		podName, podNamespace := getPodInfo(args.Args)
 		utils.Logger.Debug(fmt.Sprintf("RequestIP of %s net to %s:%d for %s/%s with %v", ipamConf.Name, ipamConf.DaemonIP, ipamConf.DaemonPort, podNamespace, podName, n.Masters))
-		ipResponses, err := RequestIP(ipamConf.DaemonIP, ipamConf.DaemonPort, podName, podNamespace, hostName, ipamConf.Name, n.Masters)
+		ipResponses, err := RequestIP(ipamConf.DaemonIP, ipamConf.DaemonPort, n.DaemonClientConfig, podName, podNamespace, hostName, ipamConf.Name, n.Masters)
 
 		if err != nil {
+			if cniErr, ok := err.(*types.Error); ok {
+				// keep daemon reason code to show in pod events
+				return cniErr
+			}
 			return fmt.Errorf("failed to request ip %v", err)
 		}
 
@@ -268,7 +273,7 @@ func cmdDel(args *skel.CmdArgs) error {
 	}
 	podName, podNamespace := getPodInfo(args.Args)
 	utils.Logger.Debug(fmt.Sprintf("RequestDeallocateIP of %s/%s in %s net from %s:%d", podNamespace, podName, ipamConf.Name, ipamConf.DaemonIP, ipamConf.DaemonPort))
-	ipResponses, err := Deallocate(ipamConf.DaemonPort, podName, podNamespace, hostName, ipamConf.Name)
+	ipResponses, err := Deallocate(ipamConf.DaemonPort, n.DaemonClientConfig, podName, podNamespace, hostName, ipamConf.Name)
 	utils.Logger.Debug(fmt.Sprintf("ResponseDeallocateIP: %v", ipResponses))
 
 	for index, master := range n.Masters {
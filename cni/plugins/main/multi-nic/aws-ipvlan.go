@@ -67,7 +67,10 @@ func loadAWSCNIConf(bytes []byte, ifName string, n *NetConf, ipConfigs []*curren
 		if singleConfig.CNIVersion == "" {
 			singleConfig.CNIVersion = n.CNIVersion
 		}
-		singleConfig.Name = fmt.Sprintf("%s-%d", ifName, index)
+		singleConfig.Name, err = n.getInterfaceName(ifName, index, ipConfigs)
+		if err != nil {
+			return confBytesArray, err
+		}
 		singleConfig.Master = masterName
 		confBytes, err := json.Marshal(singleConfig)
 		if err != nil {
@@ -64,7 +64,10 @@ func loadHostDeviceConf(bytes []byte, ifName string, n *NetConf, ipConfigs []*cu
 		if singleConfig.CNIVersion == "" {
 			singleConfig.CNIVersion = n.CNIVersion
 		}
-		singleConfig.Name = fmt.Sprintf("%s-%d", ifName, index)
+		singleConfig.Name, err = n.getInterfaceName(ifName, index, ipConfigs)
+		if err != nil {
+			return confBytesArray, err
+		}
 		singleConfig.RuntimeConfig = HostDeviceRuntimeConfig{
 			DeviceID: deviceID,
 		}
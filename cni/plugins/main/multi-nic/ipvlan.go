@@ -7,7 +7,6 @@ package main
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/containernetworking/cni/pkg/types"
 	current "github.com/containernetworking/cni/pkg/types/100"
@@ -51,8 +50,12 @@ func loadIPVANConf(bytes []byte, ifName string, n *NetConf, ipConfigs []*current
 		if singleConfig.CNIVersion == "" {
 			singleConfig.CNIVersion = n.CNIVersion
 		}
-		singleConfig.Name = fmt.Sprintf("%s-%d", ifName, index)
+		singleConfig.Name, err = n.getInterfaceName(ifName, index, ipConfigs)
+		if err != nil {
+			return confBytesArray, err
+		}
 		singleConfig.Master = masterName
+		singleConfig.MTU = adaptMTU(singleConfig.MTU, masterName)
 		confBytes, err := json.Marshal(singleConfig)
 		if err != nil {
 			return confBytesArray, err
@@ -8,7 +8,6 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 
 	"github.com/containernetworking/cni/pkg/skel"
@@ -41,9 +40,16 @@ type NetConf struct {
 	IsMultiNICIPAM bool                   `json:"multiNICIPAM,omitempty"`
 	DaemonIP       string                 `json:"daemonIP"`
 	DaemonPort     int                    `json:"daemonPort"`
-	Args           *struct {
+	IfNameTemplate string                 `json:"ifNameTemplate,omitempty"`
+	utils.DaemonClientConfig
+	Args *struct {
 		NicSet *NicArgs `json:"cni,omitempty"`
 	} `json:"args"`
+	// selectedNetAddrs are network addresses of selected masters in the same order
+	selectedNetAddrs []string
+	interfaceBlock   int
+	// fallbackDefaultName uses default interface name if ifNameTemplate cannot be resolved such as on DEL
+	fallbackDefaultName bool
 }
 
 // NicArgs defines additional specification in pod annotation
@@ -63,7 +69,7 @@ func cmdAdd(args *skel.CmdArgs) error {
 	// load general NetConf and get deviceType
 	n, deviceType, err := loadConf(args)
 	if err != nil {
-		return fmt.Errorf("failed to load netconf: %v", err)
+		return annotateError(err, "failed to load netconf")
 	}
 	utils.Logger.Debug(fmt.Sprintf("Received an ADD request for: conf=%v", n))
 
@@ -105,6 +111,10 @@ func cmdAdd(args *skel.CmdArgs) error {
 		injectedStdIn := injectMaster(args.StdinData, n.MasterNetAddrs, n.Masters, n.DeviceIDs)
 		r, err := ipam.ExecAdd(n.IPAM.Type, injectedStdIn)
 		if err != nil {
+			if _, ok := err.(*types.Error); ok {
+				utils.Logger.Debug(fmt.Sprintf("IPAM ExecAdd: %v, %s", err, string(injectedStdIn)))
+				return annotateError(err, "IPAM ExecAdd")
+			}
 			return fmt.Errorf("IPAM ExecAdd: %v, %s", err, string(injectedStdIn))
 		}
 
@@ -151,34 +161,33 @@ func cmdAdd(args *skel.CmdArgs) error {
 		return fmt.Errorf("zero config %v", n)
 	}
 
+	tuning, err := loadInterfaceTuning(n.MainPlugin)
+	if err != nil {
+		return fmt.Errorf("failed to load interface tuning: %v", err)
+	}
+
 	ips := []*current.IPConfig{}
+	ifNames := make(map[string]bool)
 	for index, confBytes := range confBytesArray {
 		command := "ADD"
-		ifName := fmt.Sprintf("%s-%d", args.IfName, index)
+		ifName := getDelegateName(confBytes, fmt.Sprintf("%s-%d", args.IfName, index))
+		if ifNames[ifName] {
+			return fmt.Errorf("duplicate interface name %s from template %q", ifName, n.IfNameTemplate)
+		}
+		ifNames[ifName] = true
 		utils.Logger.Debug(fmt.Sprintf("Exec %s %s: %s", command, ifName, string(confBytes)))
 		executeResult, err := execPlugin(deviceType, command, confBytes, args, ifName, true)
 		if err != nil {
 			utils.Logger.Debug(fmt.Sprintf("Fail execPlugin %v: %v", string(confBytes), err))
 			return err
 		}
-		interfaceItem := &current.Interface{}
-		interfaceItem.Name = ifName
-
-		err = netns.Do(func(_ ns.NetNS) error {
-			link, err := net.InterfaceByName(ifName)
-			if err != nil {
-				return err
-			}
-			interfaceItem.Mac = link.HardwareAddr.String()
-			interfaceItem.Sandbox = netns.Path()
-			return nil
-		})
-		result.Interfaces = append(result.Interfaces, interfaceItem)
-		if len(executeResult.IPs) > 0 {
-			ipConf := executeResult.IPs[0]
-			ipConf.Interface = current.Int(index)
-			ips = append(ips, ipConf)
+		if err = tuning.apply(deviceType, netns, ifName); err != nil {
+			utils.Logger.Debug(fmt.Sprintf("Fail tuning %s: %v", ifName, err))
+			return err
 		}
+		// keep interface indexes of IPs valid for chained plugins
+		master := getDelegateMaster(confBytes)
+		ips = append(ips, mergeResult(result, executeResult, netns, ifName, master)...)
 	}
 	result.IPs = ips
 	utils.Logger.Debug(fmt.Sprintf("Result: %v", result))
@@ -196,6 +205,8 @@ func cmdDel(args *skel.CmdArgs) error {
 		return nil
 	}
 	utils.Logger.Debug(fmt.Sprintf("Received an DEL request for: conf=%v", n))
+	// deallocation may fail, do not abort DEL by unresolved interface name
+	n.fallbackDefaultName = true
 	// On chained invocation, IPAM block can be empty
 	if n.IPAM.Type != "" {
 		injectedStdIn := injectMaster(args.StdinData, n.MasterNetAddrs, n.Masters, n.DeviceIDs)
@@ -247,7 +258,7 @@ func cmdDel(args *skel.CmdArgs) error {
 
 	for index, confBytes := range confBytesArray {
 		command := "DEL"
-		ifName := fmt.Sprintf("%s-%d", args.IfName, index)
+		ifName := getDelegateName(confBytes, fmt.Sprintf("%s-%d", args.IfName, index))
 		utils.Logger.Debug(fmt.Sprintf("Exec %s %s: %s", command, ifName, string(confBytes)))
 		_, err := execPlugin(deviceType, command, confBytes, args, ifName, false)
 		if err != nil {
@@ -310,7 +321,7 @@ func cmdCheck(args *skel.CmdArgs) error {
 
 	for index, confBytes := range confBytesArray {
 		command := "CHECK"
-		ifName := fmt.Sprintf("%s-%d", args.IfName, index)
+		ifName := getDelegateName(confBytes, fmt.Sprintf("%s-%d", args.IfName, index))
 		utils.Logger.Debug(fmt.Sprintf("Exec %s %s: %s", command, ifName, string(confBytes)))
 		_, err := execPlugin(deviceType, command, confBytes, args, ifName, false)
 		if err != nil {
@@ -342,12 +353,16 @@ func loadConf(args *skel.CmdArgs) (*NetConf, string, error) {
 	if n.Args != nil && n.Args.NicSet != nil {
 		nicSet = *n.Args.NicSet
 	}
-	selectResponse, err := selectNICs(n.DaemonIP, n.DaemonPort, podName, podNamespace, hostName, n.Name, nicSet, n.MasterNetAddrs)
+	selectResponse, err := selectNICs(n.DaemonIP, n.DaemonPort, n.DaemonClientConfig, podName, podNamespace, hostName, n.Name, nicSet, n.MasterNetAddrs)
 	if err != nil {
 		return n, deviceType, err
 	}
 	n.Masters = selectResponse.Masters
 	n.DeviceIDs = selectResponse.DeviceIDs
+	n.selectedNetAddrs = selectResponse.MasterNetAddrs
+	if err = n.loadInterfaceNaming(args.StdinData); err != nil {
+		return n, deviceType, err
+	}
 
 	return n, deviceType, nil
 }
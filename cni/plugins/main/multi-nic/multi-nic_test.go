@@ -292,11 +292,22 @@ func (t *testerV10x) verifyResult(result types.Result, name string) string {
 	r, err := types100.GetResult(result)
 	Expect(err).NotTo(HaveOccurred())
 
-	Expect(len(r.Interfaces)).To(Equal(len(MASTER_NAMES)))
-	Expect(r.Interfaces[0].Name).To(Equal(name))
+	sandboxInterfaces := []*types100.Interface{}
+	for _, iface := range r.Interfaces {
+		if iface.Sandbox != "" {
+			sandboxInterfaces = append(sandboxInterfaces, iface)
+		}
+	}
+	Expect(len(sandboxInterfaces)).To(Equal(len(MASTER_NAMES)))
+	Expect(sandboxInterfaces[0].Name).To(Equal(name + "-0"))
 	Expect(len(r.IPs)).To(Equal(len(MASTER_NAMES)))
+	// IPs must refer to sandbox interfaces
+	for _, ip := range r.IPs {
+		Expect(ip.Interface).NotTo(BeNil())
+		Expect(r.Interfaces[*ip.Interface].Sandbox).NotTo(BeEmpty())
+	}
 
-	return r.Interfaces[0].Mac
+	return sandboxInterfaces[0].Mac
 }
 
 // verifyResult minimally verifies the Result and returns the interface's MAC address
@@ -304,11 +315,22 @@ func (t *testerV04x) verifyResult(result types.Result, name string) string {
 	r, err := types040.GetResult(result)
 	Expect(err).NotTo(HaveOccurred())
 
-	Expect(len(r.Interfaces)).To(Equal(len(MASTER_NAMES)))
-	Expect(r.Interfaces[0].Name).To(Equal(name + "-0"))
+	sandboxInterfaces := []*types040.Interface{}
+	for _, iface := range r.Interfaces {
+		if iface.Sandbox != "" {
+			sandboxInterfaces = append(sandboxInterfaces, iface)
+		}
+	}
+	Expect(len(sandboxInterfaces)).To(Equal(len(MASTER_NAMES)))
+	Expect(sandboxInterfaces[0].Name).To(Equal(name + "-0"))
 	Expect(len(r.IPs)).To(Equal(len(MASTER_NAMES)))
+	// IPs must refer to sandbox interfaces
+	for _, ip := range r.IPs {
+		Expect(ip.Interface).NotTo(BeNil())
+		Expect(r.Interfaces[*ip.Interface].Sandbox).NotTo(BeEmpty())
+	}
 
-	return r.Interfaces[0].Mac
+	return sandboxInterfaces[0].Mac
 }
 
 // verifyResult minimally verifies the Result and returns the interface's MAC address
@@ -618,3 +640,77 @@ func startDaemonServer(httpServerExitDone *sync.WaitGroup) *http.Server {
 
 	return srv
 }
+
+var _ = Describe("Interface naming", func() {
+	newNetConf := func(template string) *NetConf {
+		n := &NetConf{
+			Subnet:           "192.168.0.0/16",
+			MasterNetAddrs:   POOL_NETWORK_ADDRESSES,
+			Masters:          []string{"eth2", "eth0"},
+			selectedNetAddrs: []string{"10.244.2.0/24", "10.244.0.0/24"},
+			IsMultiNICIPAM:   true,
+			IfNameTemplate:   template,
+		}
+		Expect(n.loadInterfaceNaming([]byte(`{"ipam":{"interfaceBlock":2}}`))).To(Succeed())
+		return n
+	}
+	ipConfigs := []*types100.IPConfig{
+		{Address: net.IPNet{IP: net.ParseIP("192.168.128.5"), Mask: net.CIDRMask(24, 32)}, Interface: types100.Int(0)},
+		{Address: net.IPNet{IP: net.ParseIP("192.168.64.5"), Mask: net.CIDRMask(24, 32)}, Interface: types100.Int(1)},
+	}
+
+	It("keeps default name", func() {
+		name, err := newNetConf("").getInterfaceName("net1", 1, ipConfigs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("net1-1"))
+	})
+
+	It("renders template", func() {
+		n := newNetConf("{ifname}-{master}")
+		name, err := n.getInterfaceName("net1", 0, ipConfigs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("net1-eth2"))
+
+		n = newNetConf("rail{netIndex}")
+		name, err = n.getInterfaceName("net1", 0, ipConfigs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("rail2"))
+		name, err = n.getInterfaceName("net1", 1, ipConfigs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("rail0"))
+
+		n = newNetConf("net{interfaceIndex}")
+		name, err = n.getInterfaceName("net1", 0, ipConfigs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("net2"))
+		name, err = n.getInterfaceName("net1", 1, ipConfigs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("net1"))
+	})
+
+	It("looks up address of interface", func() {
+		n := newNetConf("net{interfaceIndex}")
+		// master 0 has no address
+		name, err := n.getInterfaceName("net1", 1, ipConfigs[1:])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("net1"))
+		_, err = n.getInterfaceName("net1", 0, ipConfigs[1:])
+		Expect(err).To(HaveOccurred())
+		n.fallbackDefaultName = true
+		name, err = n.getInterfaceName("net1", 0, ipConfigs[1:])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("net1-0"))
+	})
+
+	It("rejects invalid template", func() {
+		n := &NetConf{IfNameTemplate: "{ifname}-{rail}"}
+		Expect(n.loadInterfaceNaming([]byte(`{}`))).NotTo(Succeed())
+		_, err := newNetConf("{ifname}-{master}-{netIndex}").getInterfaceName("net1-long", 0, ipConfigs)
+		Expect(err).To(HaveOccurred())
+		n = newNetConf("rail{netIndex}")
+		n.selectedNetAddrs = []string{"10.10.0.0/24"}
+		n.Masters = []string{"not-exist"}
+		_, err = n.getInterfaceName("net1", 0, ipConfigs)
+		Expect(err).To(HaveOccurred())
+	})
+})
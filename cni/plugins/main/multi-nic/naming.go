@@ -0,0 +1,181 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	current "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/plugins/pkg/utils"
+)
+
+const (
+	IFNAME_KEY          = "{ifname}"
+	INDEX_KEY           = "{index}"
+	MASTER_KEY          = "{master}"
+	NET_INDEX_KEY       = "{netIndex}"
+	INTERFACE_INDEX_KEY = "{interfaceIndex}"
+	// IFNAMSIZ - 1
+	MAX_IFNAME_LENGTH = 15
+)
+
+var ifNameTemplateKeys = []string{IFNAME_KEY, INDEX_KEY, MASTER_KEY, NET_INDEX_KEY, INTERFACE_INDEX_KEY}
+
+// loadInterfaceNaming validates ifNameTemplate and reads interface block of multi-NIC IPAM
+func (n *NetConf) loadInterfaceNaming(stdinData []byte) error {
+	if n.IfNameTemplate == "" {
+		return nil
+	}
+	remaining := n.IfNameTemplate
+	for _, key := range ifNameTemplateKeys {
+		remaining = strings.ReplaceAll(remaining, key, "")
+	}
+	if strings.ContainsAny(remaining, "{}/ \t\n") {
+		return fmt.Errorf("invalid ifNameTemplate %q: supported placeholders are %s", n.IfNameTemplate, strings.Join(ifNameTemplateKeys, ", "))
+	}
+	ipamConf := &struct {
+		IPAM struct {
+			InterfaceBlock int `json:"interfaceBlock"`
+		} `json:"ipam"`
+	}{}
+	if err := json.Unmarshal(stdinData, ipamConf); err != nil {
+		return err
+	}
+	n.interfaceBlock = ipamConf.IPAM.InterfaceBlock
+	if n.interfaceBlock == 0 {
+		n.interfaceBlock = DEFAULT_INTERFACE_BLOCK
+	}
+	return nil
+}
+
+// getInterfaceName returns pod interface name of the master at index,
+// default name is {ifname}-{index}, which is also returned on DEL if the template cannot be resolved
+func (n *NetConf) getInterfaceName(ifName string, index int, ipConfigs []*current.IPConfig) (string, error) {
+	defaultName := fmt.Sprintf("%s-%d", ifName, index)
+	if n.IfNameTemplate == "" {
+		return defaultName, nil
+	}
+	name, err := n.renderInterfaceName(ifName, index, ipConfigs)
+	if err != nil && n.fallbackDefaultName {
+		utils.Logger.Debug(fmt.Sprintf("use default name %s: %v", defaultName, err))
+		return defaultName, nil
+	}
+	return name, err
+}
+
+// renderInterfaceName returns pod interface name of the master at index from ifNameTemplate
+func (n *NetConf) renderInterfaceName(ifName string, index int, ipConfigs []*current.IPConfig) (string, error) {
+	name := n.IfNameTemplate
+	name = strings.ReplaceAll(name, IFNAME_KEY, ifName)
+	name = strings.ReplaceAll(name, INDEX_KEY, strconv.Itoa(index))
+	if strings.Contains(name, MASTER_KEY) {
+		if index >= len(n.Masters) || n.Masters[index] == "" {
+			return "", fmt.Errorf("cannot resolve %s of interface %d", MASTER_KEY, index)
+		}
+		name = strings.ReplaceAll(name, MASTER_KEY, n.Masters[index])
+	}
+	if strings.Contains(name, NET_INDEX_KEY) {
+		netIndex, err := n.getNetIndex(index)
+		if err != nil {
+			return "", err
+		}
+		name = strings.ReplaceAll(name, NET_INDEX_KEY, strconv.Itoa(netIndex))
+	}
+	if strings.Contains(name, INTERFACE_INDEX_KEY) {
+		interfaceIndex, err := n.getInterfaceIndex(index, ipConfigs)
+		if err != nil {
+			return "", err
+		}
+		name = strings.ReplaceAll(name, INTERFACE_INDEX_KEY, strconv.Itoa(interfaceIndex))
+	}
+	if len(name) > MAX_IFNAME_LENGTH {
+		return "", fmt.Errorf("interface name %s from template %q exceeds %d characters", name, n.IfNameTemplate, MAX_IFNAME_LENGTH)
+	}
+	return name, nil
+}
+
+// getNetIndex returns position of the master network address in masterNets
+func (n *NetConf) getNetIndex(index int) (int, error) {
+	netAddress := ""
+	if index < len(n.selectedNetAddrs) {
+		netAddress = n.selectedNetAddrs[index]
+	}
+	if netAddress == "" && index < len(n.Masters) {
+		// daemon does not report network address, look up master on host
+		netAddress = getNetAddressFromInterfaceName(n.Masters[index])
+	}
+	for netIndex, masterNetAddr := range n.MasterNetAddrs {
+		if netAddress != "" && masterNetAddr == netAddress {
+			return netIndex, nil
+		}
+	}
+	return -1, fmt.Errorf("cannot resolve %s of interface %d: network %q not in %v", NET_INDEX_KEY, index, netAddress, n.MasterNetAddrs)
+}
+
+// getIPConfig returns IP config assigned to the master at index
+func getIPConfig(index int, ipConfigs []*current.IPConfig) *current.IPConfig {
+	for _, ipConfig := range ipConfigs {
+		if ipConfig.Interface != nil && *ipConfig.Interface == index {
+			return ipConfig
+		}
+	}
+	return nil
+}
+
+// getInterfaceIndex returns CIDR interface index from the multi-NIC IPAM address
+// which is located in the interface block next to the subnet prefix
+func (n *NetConf) getInterfaceIndex(index int, ipConfigs []*current.IPConfig) (int, error) {
+	ipConfig := getIPConfig(index, ipConfigs)
+	if !n.IsMultiNICIPAM || ipConfig == nil {
+		return -1, fmt.Errorf("cannot resolve %s of interface %d: no multi-NIC IPAM address", INTERFACE_INDEX_KEY, index)
+	}
+	_, subnet, err := net.ParseCIDR(n.Subnet)
+	if err != nil {
+		return -1, err
+	}
+	ip := ipConfig.Address.IP.To4()
+	prefix, _ := subnet.Mask.Size()
+	if ip == nil || !subnet.Contains(ip) || prefix+n.interfaceBlock > 32 {
+		return -1, fmt.Errorf("cannot resolve %s of interface %d: %v not in %s", INTERFACE_INDEX_KEY, index, ipConfig.Address.IP, n.Subnet)
+	}
+	shift := 32 - prefix - n.interfaceBlock
+	mask := uint32(1)<<n.interfaceBlock - 1
+	return int(binary.BigEndian.Uint32(ip) >> shift & mask), nil
+}
+
+// getNetAddressFromInterfaceName returns network address of host interface
+func getNetAddressFromInterfaceName(name string) string {
+	link, err := net.InterfaceByName(name)
+	if err != nil {
+		return ""
+	}
+	addrs, err := link.Addrs()
+	if err != nil {
+		return ""
+	}
+	for _, a := range addrs {
+		if v, ok := a.(*net.IPNet); ok && v.IP.To4() != nil {
+			return getNetAddress(v)
+		}
+	}
+	return ""
+}
+
+// getDelegateName returns pod interface name set as name of delegate config
+func getDelegateName(confBytes []byte, defaultName string) string {
+	conf := &struct {
+		Name string `json:"name"`
+	}{}
+	if err := json.Unmarshal(confBytes, conf); err != nil || conf.Name == "" {
+		return defaultName
+	}
+	return conf.Name
+}
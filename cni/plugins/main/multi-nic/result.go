@@ -0,0 +1,109 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	current "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containernetworking/plugins/pkg/utils"
+)
+
+// getDelegateMaster returns master host interface name of delegate config
+func getDelegateMaster(confBytes []byte) string {
+	conf := &struct {
+		Master string `json:"master"`
+	}{}
+	if err := json.Unmarshal(confBytes, conf); err != nil {
+		return ""
+	}
+	return conf.Master
+}
+
+// getHostInterface returns host-side interface if master remains in host namespace
+func getHostInterface(master string) *current.Interface {
+	if master == "" {
+		return nil
+	}
+	link, err := net.InterfaceByName(master)
+	if err != nil {
+		// master is moved to the pod (e.g., host-device)
+		return nil
+	}
+	return &current.Interface{
+		Name: link.Name,
+		Mac:  link.HardwareAddr.String(),
+	}
+}
+
+// getSandboxInterface returns sandbox interface by looking up ifName in netns
+func getSandboxInterface(netns ns.NetNS, ifName string) *current.Interface {
+	interfaceItem := &current.Interface{
+		Name:    ifName,
+		Sandbox: netns.Path(),
+	}
+	err := netns.Do(func(_ ns.NetNS) error {
+		link, err := net.InterfaceByName(ifName)
+		if err != nil {
+			return err
+		}
+		interfaceItem.Mac = link.HardwareAddr.String()
+		return nil
+	})
+	if err != nil {
+		utils.Logger.Debug(fmt.Sprintf("cannot get sandbox interface %s: %v", ifName, err))
+	}
+	return interfaceItem
+}
+
+// mergeResult appends host-side and sandbox interfaces of a delegate result to the merged result
+// and returns the delegate IPs with interface index pointing to the merged sandbox interface
+func mergeResult(result *current.Result, delegateResult *current.Result, netns ns.NetNS, ifName, master string) []*current.IPConfig {
+	hasHostInterface := false
+	for _, iface := range delegateResult.Interfaces {
+		if iface.Sandbox == "" {
+			hasHostInterface = true
+			break
+		}
+	}
+	if !hasHostInterface {
+		if hostInterface := getHostInterface(master); hostInterface != nil {
+			result.Interfaces = append(result.Interfaces, hostInterface)
+		}
+	}
+
+	sandboxIndex := -1
+	indexMap := make(map[int]int)
+	for index, iface := range delegateResult.Interfaces {
+		copied := *iface
+		indexMap[index] = len(result.Interfaces)
+		if copied.Name == ifName && copied.Sandbox != "" {
+			sandboxIndex = len(result.Interfaces)
+		}
+		result.Interfaces = append(result.Interfaces, &copied)
+	}
+	if sandboxIndex == -1 {
+		sandboxIndex = len(result.Interfaces)
+		result.Interfaces = append(result.Interfaces, getSandboxInterface(netns, ifName))
+	}
+
+	ips := []*current.IPConfig{}
+	for _, ip := range delegateResult.IPs {
+		ipConf := ip.Copy()
+		mergedIndex := sandboxIndex
+		if ipConf.Interface != nil {
+			if index, ok := indexMap[*ipConf.Interface]; ok {
+				mergedIndex = index
+			}
+		}
+		ipConf.Interface = current.Int(mergedIndex)
+		ips = append(ips, ipConf)
+	}
+	return ips
+}
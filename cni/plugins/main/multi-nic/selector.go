@@ -8,12 +8,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"time"
 
-	"bytes"
-	"errors"
+	"github.com/containernetworking/plugins/pkg/utils"
 )
 
 const (
@@ -32,11 +28,12 @@ type NICSelectRequest struct {
 }
 
 type NICSelectResponse struct {
-	DeviceIDs []string `json:"deviceIDs"`
-	Masters   []string `json:"masters"`
+	DeviceIDs      []string `json:"deviceIDs"`
+	Masters        []string `json:"masters"`
+	MasterNetAddrs []string `json:"masterNets,omitempty"`
 }
 
-func selectNICs(daemonIP string, daemonPort int, podName string, podNamespace string, hostName string, defName string, nicSet NicArgs, masterNets []string) (NICSelectResponse, error) {
+func selectNICs(daemonIP string, daemonPort int, clientConfig utils.DaemonClientConfig, podName string, podNamespace string, hostName string, defName string, nicSet NicArgs, masterNets []string) (NICSelectResponse, error) {
 	var response NICSelectResponse
 	if daemonPort == 0 {
 		daemonPort = DEFAULT_DAEMON_PORT
@@ -44,7 +41,6 @@ func selectNICs(daemonIP string, daemonPort int, podName string, podNamespace st
 	if daemonIP == "" {
 		daemonIP = DEFAULT_DAEMON_IP
 	}
-	address := fmt.Sprintf("http://%s:%d/%s", daemonIP, daemonPort, NIC_SELECT_PATH)
 	request := NICSelectRequest{
 		PodName:          podName,
 		PodNamespace:     podNamespace,
@@ -54,32 +50,13 @@ func selectNICs(daemonIP string, daemonPort int, podName string, podNamespace st
 		NicSet:           nicSet,
 	}
 
-	jsonReq, err := json.Marshal(request)
-
+	body, err := clientConfig.PostDaemon(daemonIP, daemonPort, NIC_SELECT_PATH, request)
 	if err != nil {
-		return response, fmt.Errorf("marshal fail: %v", err)
-	} else {
-		client := http.Client{
-			Timeout: 5 * time.Minute,
-		}
-		defer client.CloseIdleConnections()
-		res, err := client.Post(address, "application/json; charset=utf-8", bytes.NewBuffer(jsonReq))
-		if err != nil {
-			return response, fmt.Errorf("post fail: %v", err)
-		}
-		defer res.Body.Close()
-		if res.StatusCode != http.StatusOK {
-			return response, errors.New(res.Status)
-		}
-
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return response, fmt.Errorf("read body: %v", err)
-		}
-		err = json.Unmarshal(body, &response)
-		if err == nil && len(response.Masters) == 0 {
-			return response, fmt.Errorf("response nothing")
-		}
 		return response, err
 	}
+	err = json.Unmarshal(body, &response)
+	if err == nil && len(response.Masters) == 0 {
+		return response, fmt.Errorf("response nothing")
+	}
+	return response, err
 }
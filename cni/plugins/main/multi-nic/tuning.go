@@ -0,0 +1,120 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containernetworking/plugins/pkg/utils"
+	"github.com/containernetworking/plugins/pkg/utils/sysctl"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	IFNAME_PLACEHOLDER  = "IFNAME"
+	DEFAULT_IPVLAN_MODE = "l2"
+)
+
+var ipvlanModes = map[string]netlink.IPVlanMode{
+	"l2":  netlink.IPVLAN_MODE_L2,
+	"l3":  netlink.IPVLAN_MODE_L3,
+	"l3s": netlink.IPVLAN_MODE_L3S,
+}
+
+var ipvlanFlags = map[string]netlink.IPVlanFlag{
+	"bridge":  netlink.IPVLAN_FLAG_BRIDGE,
+	"private": netlink.IPVLAN_FLAG_PRIVATE,
+	"vepa":    netlink.IPVLAN_FLAG_VEPA,
+}
+
+// InterfaceTuning defines settings applied to each pod interface after delegate ADD
+type InterfaceTuning struct {
+	Mode    string            `json:"mode"`
+	Flag    string            `json:"flag,omitempty"`
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+}
+
+// loadInterfaceTuning reads tuning settings from main plugin config
+func loadInterfaceTuning(mainPlugin map[string]interface{}) (*InterfaceTuning, error) {
+	tuning := &InterfaceTuning{}
+	pluginBytes, err := json.Marshal(mainPlugin)
+	if err != nil {
+		return tuning, err
+	}
+	err = json.Unmarshal(pluginBytes, tuning)
+	return tuning, err
+}
+
+// apply sets ipvlan flag and sysctls of the pod interface
+func (t *InterfaceTuning) apply(deviceType string, netns ns.NetNS, ifName string) error {
+	if t.Flag == "" && len(t.Sysctls) == 0 {
+		return nil
+	}
+	return netns.Do(func(_ ns.NetNS) error {
+		if deviceType == "ipvlan" && t.Flag != "" {
+			if err := t.setIPVlanFlag(ifName); err != nil {
+				return fmt.Errorf("failed to set ipvlan flag %s to %s: %v", t.Flag, ifName, err)
+			}
+		}
+		for key, value := range t.Sysctls {
+			key = strings.ReplaceAll(key, IFNAME_PLACEHOLDER, ifName)
+			if _, err := sysctl.Sysctl(key, value); err != nil {
+				return fmt.Errorf("failed to set sysctl %s=%s: %v", key, value, err)
+			}
+		}
+		return nil
+	})
+}
+
+// setIPVlanFlag sets ipvlan mode and flag of the pod interface in the current netns
+func (t *InterfaceTuning) setIPVlanFlag(ifName string) error {
+	modeStr := t.Mode
+	if modeStr == "" {
+		modeStr = DEFAULT_IPVLAN_MODE
+	}
+	mode, found := ipvlanModes[modeStr]
+	if !found {
+		return fmt.Errorf("unknown ipvlan mode %s", modeStr)
+	}
+	flag, found := ipvlanFlags[t.Flag]
+	if !found {
+		return fmt.Errorf("unknown ipvlan flag %s", t.Flag)
+	}
+	handle, err := netlink.NewHandle()
+	if err != nil {
+		return err
+	}
+	defer handle.Delete()
+	link, err := handle.LinkByName(ifName)
+	if err != nil {
+		return err
+	}
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = ifName
+	attrs.Index = link.Attrs().Index
+	attrs.Flags = link.Attrs().Flags
+	return handle.LinkModify(&netlink.IPVlan{LinkAttrs: attrs, Mode: mode, Flag: flag})
+}
+
+// adaptMTU returns master MTU if the requested MTU is larger than that of master
+func adaptMTU(mtu int, master string) int {
+	if mtu <= 0 {
+		return mtu
+	}
+	link, err := net.InterfaceByName(master)
+	if err != nil {
+		return mtu
+	}
+	if link.MTU < mtu {
+		utils.Logger.Debug(fmt.Sprintf("adapt mtu %d to %d of master %s", mtu, link.MTU, master))
+		return link.MTU
+	}
+	return mtu
+}
@@ -14,6 +14,7 @@ import (
 
 	"fmt"
 
+	"github.com/containernetworking/cni/pkg/types"
 	current "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/containernetworking/plugins/pkg/utils"
 	"github.com/vishvananda/netlink"
@@ -34,6 +35,14 @@ func getPodInfo(cniArgs string) (string, string) {
 	return podName, podNamespace
 }
 
+// annotateError adds context to error and keeps the code if it is CNI error
+func annotateError(err error, msg string) error {
+	if cniErr, ok := err.(*types.Error); ok {
+		return types.NewError(cniErr.Code, fmt.Sprintf("%s: %s", msg, cniErr.Msg), cniErr.Details)
+	}
+	return fmt.Errorf("%s: %v", msg, err)
+}
+
 // injectIPAM injects ipam bytes to config
 
 func injectMultiNicIPAM(singleNicConfBytes []byte, ipConfigs []*current.IPConfig, ipIndex int) []byte {
@@ -436,13 +436,16 @@ func (h *CIDRHandler) UpdateEntries(cidrSpec multinicv1.CIDRSpec, excludes []com
 		// assign interface index to each host
 		for _, iface := range ifaces {
 			interfaceNetAddress := iface.NetAddress
+			hostIP := iface.HostIP
 			if len(def.MasterNetAddrs) > 0 {
-				if _, found := checkInterfaceMap[interfaceNetAddress]; !found {
+				addr, found := getMasterAddress(iface, checkInterfaceMap)
+				if !found {
 					continue
 				}
+				interfaceNetAddress = addr.NetAddress
+				hostIP = addr.HostIP
 			}
 			interfaceName := iface.InterfaceName
-			hostIP := iface.HostIP
 			success, entry := h.getInterfaceEntry(def, entriesMap, interfaceNetAddress)
 			if !success {
 				continue
@@ -616,7 +619,15 @@ func (h *CIDRHandler) SyncCIDRRoute(cidrSpec multinicv1.CIDRSpec, forceDelete bo
 		h.Mutex.Unlock()
 		hostInterfaceInfoMap := h.GetHostInterfaceIndexMap(entries)
 		vars.CIDRLog.V(3).Info(fmt.Sprintf("Sync routes from CIDR (force delete: %v)", forceDelete))
-		success, noConnection := h.RouteHandler.AddRoutes(cidrSpec, entries, hostInterfaceInfoMap, forceDelete)
+		policy := h.getRolloutPolicy(def.Name)
+		success, noConnection, rollout := h.RouteHandler.AddRoutes(cidrSpec, entries, hostInterfaceInfoMap, forceDelete, policy)
+		if policy != nil {
+			vars.CIDRLog.V(3).Info(fmt.Sprintf("Route rollout of %s: %d/%d updated, %d failed, halted=%v", def.Name, rollout.Updated, rollout.Total, rollout.Failed, rollout.Halted))
+			h.MultiNicNetworkHandler.UpdateRolloutStatus(def.Name, &rollout)
+		}
+		if rollout.Halted {
+			return multinicv1.SomeRouteFailed
+		}
 		if noConnection {
 			return multinicv1.RouteUnknown
 		}
@@ -848,6 +859,17 @@ func (h *CIDRHandler) addNewHost(hosts []multinicv1.HostInterfaceInfo, maxHostIn
 	}
 }
 
+// getMasterAddress returns the first address of interface in master network addresses,
+// so that interface with multiple addresses is assigned to a single entry
+func getMasterAddress(iface multinicv1.InterfaceInfoType, masterNetAddrs map[string]bool) (multinicv1.InterfaceAddress, bool) {
+	for _, addr := range iface.GetAddresses() {
+		if masterNetAddrs[addr.NetAddress] {
+			return addr, true
+		}
+	}
+	return multinicv1.InterfaceAddress{}, false
+}
+
 // getInterfaceEntry get entry from interfaceaddress if exists, otherwise create new
 func (h *CIDRHandler) getInterfaceEntry(def multinicv1.PluginConfig, entriesMap map[string]multinicv1.CIDREntry, newNetAdress string) (bool, multinicv1.CIDREntry) {
 	if entry, found := entriesMap[newNetAdress]; found {
@@ -954,6 +976,8 @@ func (h *CIDRHandler) IsL3Mode(def multinicv1.PluginConfig) bool {
 		return true
 	case "l3s":
 		return true
+	case OVERLAY_VXLAN:
+		return true
 	default:
 		return false
 	}
@@ -1037,8 +1061,10 @@ func (h *CIDRHandler) GetHostAddressesToExclude() []string {
 	snapshot := h.HostInterfaceHandler.ListCache()
 	for _, hif := range snapshot {
 		for _, iface := range hif.Spec.Interfaces {
-			hostIPCIDR := fmt.Sprintf("%s/32", iface.HostIP)
-			excludes = append(excludes, hostIPCIDR)
+			for _, addr := range iface.GetAddresses() {
+				hostIPCIDR := fmt.Sprintf("%s/32", addr.HostIP)
+				excludes = append(excludes, hostIPCIDR)
+			}
 		}
 	}
 	return excludes
@@ -690,6 +690,169 @@ var _ = Describe("Test CIDR Handler	", func() {
 			})
 		})
 
+		Context("GetMultipathRoute", func() {
+			srcInfoMap := map[int]multinicv1.HostInterfaceInfo{
+				0: {InterfaceName: "eth1", HostIP: "10.0.1.1"},
+				1: {InterfaceName: "eth2", HostIP: "10.0.2.1"},
+				2: {InterfaceName: "eth3", HostIP: "10.0.3.1"},
+			}
+			destInfoMap := map[int]multinicv1.HostInterfaceInfo{
+				0: {InterfaceName: "eth1", HostIP: "10.0.1.2"},
+				1: {InterfaceName: "eth2", HostIP: "10.0.2.2"},
+			}
+			netAddresses := map[int]string{0: "10.0.1.0/24", 1: "10.0.2.0/24", 2: "10.0.3.0/24"}
+
+			It("returns weighted next hops over shared interfaces", func() {
+				config := multinicv1.PluginConfig{Multipath: true, MultipathWeights: map[string]int{"10.0.2.0/24": 3}}
+				route, found := GetMultipathRoute(config, "192.168.1.0/24", srcInfoMap, destInfoMap, netAddresses)
+				Expect(found).To(BeTrue())
+				Expect(route.Subnet).To(Equal("192.168.1.0/24"))
+				Expect(route.NextHops).To(Equal([]NextHop{
+					{NextHop: "10.0.1.2", InterfaceName: "eth1", Weight: 1},
+					{NextHop: "10.0.2.2", InterfaceName: "eth2", Weight: 3},
+				}))
+			})
+
+			It("returns single-path route when only one next hop remains", func() {
+				config := multinicv1.PluginConfig{Multipath: true, MultipathWeights: map[string]int{"10.0.2.0/24": 0}}
+				route, found := GetMultipathRoute(config, "192.168.1.0/24", srcInfoMap, destInfoMap, netAddresses)
+				Expect(found).To(BeTrue())
+				Expect(route.NextHops).To(BeEmpty())
+				Expect(route.NextHop).To(Equal("10.0.1.2"))
+				Expect(route.InterfaceName).To(Equal("eth1"))
+			})
+		})
+
+		Context("GetOverlayConfig", func() {
+			It("returns VNI per master from interface index", func() {
+				srcInfoMap := map[int]multinicv1.HostInterfaceInfo{
+					0: {InterfaceName: "eth1", HostIP: "10.0.1.1"},
+					2: {InterfaceName: "eth3", HostIP: "10.0.3.1"},
+				}
+				overlay := GetOverlayConfig(multinicv1.PluginConfig{VlanMode: "vxlan"}, srcInfoMap)
+				Expect(overlay.Type).To(Equal("vxlan"))
+				Expect(overlay.VNIs).To(Equal(map[string]int{"eth1": 4096, "eth3": 4098}))
+				overlay = GetOverlayConfig(multinicv1.PluginConfig{VlanMode: "vxlan", OverlayVNI: 100, OverlayPort: 8472}, srcInfoMap)
+				Expect(overlay.Port).To(Equal(8472))
+				Expect(overlay.VNIs).To(Equal(map[string]int{"eth1": 100, "eth3": 102}))
+			})
+		})
+
+		Context("GetRails", func() {
+			It("returns VLAN CIDR per interface index of the host", func() {
+				entries := []multinicv1.CIDREntry{
+					{NetAddress: "10.0.2.0/24", InterfaceIndex: 1, VlanCIDR: "192.168.64.0/18"},
+					{NetAddress: "10.0.1.0/24", InterfaceIndex: 0, VlanCIDR: "192.168.0.0/18"},
+					{NetAddress: "10.0.3.0/24", InterfaceIndex: 2, VlanCIDR: "192.168.128.0/18"},
+				}
+				srcInfoMap := map[int]multinicv1.HostInterfaceInfo{
+					0: {InterfaceName: "eth1", HostIP: "10.0.1.1"},
+					1: {InterfaceName: "eth2", HostIP: "10.0.2.1"},
+				}
+				Expect(GetRails(entries, srcInfoMap)).To(Equal([]RailConfig{
+					{Index: 0, Subnet: "192.168.0.0/18", InterfaceName: "eth1"},
+					{Index: 1, Subnet: "192.168.64.0/18", InterfaceName: "eth2"},
+				}))
+			})
+		})
+
+		Context("GetMasterAddress", func() {
+			iface := multinicv1.InterfaceInfoType{
+				InterfaceName: "eth1",
+				NetAddress:    "10.0.1.0/24",
+				HostIP:        "10.0.1.1",
+				SecondaryAddresses: []multinicv1.InterfaceAddress{
+					{NetAddress: "10.1.1.0/24", HostIP: "10.1.1.1"},
+					{NetAddress: "10.2.1.0/24", HostIP: "10.2.1.1"},
+				},
+			}
+			It("returns first address in master network addresses", func() {
+				addr, found := GetMasterAddress(iface, map[string]bool{"10.1.1.0/24": true, "10.2.1.0/24": true})
+				Expect(found).To(BeTrue())
+				Expect(addr).To(Equal(multinicv1.InterfaceAddress{NetAddress: "10.1.1.0/24", HostIP: "10.1.1.1"}))
+				addr, found = GetMasterAddress(iface, map[string]bool{"10.0.1.0/24": true, "10.2.1.0/24": true})
+				Expect(found).To(BeTrue())
+				Expect(addr.HostIP).To(Equal("10.0.1.1"))
+			})
+			It("returns not found if no address in master network addresses", func() {
+				_, found := GetMasterAddress(iface, map[string]bool{"10.3.1.0/24": true})
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("Plan", func() {
+			current := multinicv1.CIDRSpec{
+				Config: multinicv1.PluginConfig{Name: "plannet", Subnet: "192.168.0.0/16", HostBlock: 8, InterfaceBlock: 2, MasterNetAddrs: []string{"10.0.1.0/24"}},
+				CIDRs: []multinicv1.CIDREntry{{
+					NetAddress: "10.0.1.0/24",
+					VlanCIDR:   "192.168.0.0/18",
+					Hosts: []multinicv1.HostInterfaceInfo{
+						{HostIndex: 0, HostName: "host0", HostIP: "10.0.1.1", PodCIDR: "192.168.0.0/26", IPPool: "plannet-192.168.0.0-26"},
+						{HostIndex: 1, HostName: "host1", HostIP: "10.0.1.2", PodCIDR: "192.168.0.64/26", IPPool: "plannet-192.168.0.64-26"},
+					},
+				}},
+			}
+
+			It("compares IPAM config", func() {
+				config := current.Config
+				config.ExcludeCIDRs = []string{}
+				Expect(IsSameConfig(current.Config, config)).To(BeTrue())
+				config.HostBlock = 6
+				Expect(IsSameConfig(current.Config, config)).To(BeFalse())
+			})
+
+			It("lists changes and affected pods", func() {
+				planned := *current.DeepCopy()
+				planned.Config.HostBlock = 6
+				planned.CIDRs[0].Hosts[0].PodCIDR = "192.168.0.0/24"
+				planned.CIDRs[0].Hosts[0].IPPool = "plannet-192.168.0.0-24"
+				planned.CIDRs[0].Hosts = planned.CIDRs[0].Hosts[:1]
+				ippoolSnapshot := map[string]multinicv1.IPPoolSpec{
+					"plannet-192.168.0.0-26": {Allocations: []multinicv1.Allocation{{Pod: "pod-a", Namespace: "default"}}},
+				}
+				plan := DiffCIDR(current, planned, ippoolSnapshot, true)
+				Expect(plan.ID).NotTo(BeEmpty())
+				Expect(plan.VlanCIDRs).To(BeEmpty())
+				Expect(plan.HostBlocks).To(Equal([]multinicv1.PlannedChange{
+					{Action: "change", Name: "host0/10.0.1.0/24", From: "192.168.0.0/26", To: "192.168.0.0/24"},
+					{Action: "delete", Name: "host1/10.0.1.0/24", From: "192.168.0.64/26"},
+				}))
+				Expect(plan.IPPools).To(HaveLen(3))
+				Expect(plan.Routes).To(HaveLen(3))
+				Expect(plan.AffectedPods).To(Equal([]string{"default/pod-a"}))
+
+				By("keeping ID of the same plan")
+				Expect(DiffCIDR(current, planned, ippoolSnapshot, true).ID).To(Equal(plan.ID))
+			})
+		})
+
+		Context("Route rollout", func() {
+			hostNames := []string{"host0", "host1", "host2", "host3", "host4"}
+
+			It("updates one by one without policy", func() {
+				stages := GetRolloutStages(hostNames, nil)
+				Expect(stages).To(HaveLen(1))
+				Expect(stages[0]).To(HaveLen(5))
+			})
+
+			It("splits canary and batches", func() {
+				policy := &multinicv1.RolloutPolicy{MaxConcurrent: 2, CanaryPercent: 10}
+				stages := GetRolloutStages(hostNames, policy)
+				Expect(stages).To(Equal([][][]string{
+					{{"host0"}},
+					{{"host1", "host2"}, {"host3", "host4"}},
+				}))
+			})
+
+			It("halts over failure threshold", func() {
+				maxFailurePercent := 20
+				policy := &multinicv1.RolloutPolicy{MaxFailurePercent: &maxFailurePercent}
+				Expect(IsRolloutHalted(multinicv1.RolloutStatus{Updated: 5, Failed: 1}, policy)).To(BeFalse())
+				Expect(IsRolloutHalted(multinicv1.RolloutStatus{Updated: 4, Failed: 1}, policy)).To(BeTrue())
+				Expect(IsRolloutHalted(multinicv1.RolloutStatus{Updated: 4, Failed: 4}, &multinicv1.RolloutPolicy{})).To(BeFalse())
+			})
+		})
+
 		Context("Sync CIDR/IPPool", func() {
 			DescribeTable("Getting index in range",
 				func(podCIDR, testIP string, expectedContains bool, expectedIndex int) {
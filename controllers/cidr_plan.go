@@ -0,0 +1,201 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+
+	multinicv1 "github.com/foundation-model-stack/multi-nic-cni/api/v1"
+	"github.com/foundation-model-stack/multi-nic-cni/internal/compute"
+	"github.com/foundation-model-stack/multi-nic-cni/internal/vars"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	APPLY_PLAN_ANNOTATION = "multinic.fms.io/apply-plan"
+
+	PLAN_ADD    = "add"
+	PLAN_DELETE = "delete"
+	PLAN_CHANGE = "change"
+)
+
+// isSameConfig checks if IPAM config of the network is the same as the config of its CIDR
+func isSameConfig(config multinicv1.PluginConfig, cmpConfig multinicv1.PluginConfig) bool {
+	return reflect.DeepEqual(normalizeConfig(config), normalizeConfig(cmpConfig))
+}
+
+func normalizeConfig(config multinicv1.PluginConfig) multinicv1.PluginConfig {
+	if len(config.MasterNetAddrs) == 0 {
+		config.MasterNetAddrs = nil
+	}
+	if len(config.ExcludeCIDRs) == 0 {
+		config.ExcludeCIDRs = nil
+	}
+	if len(config.MultipathWeights) == 0 {
+		config.MultipathWeights = nil
+	}
+	return config
+}
+
+// PlanCIDR computes CIDR from the new config over the current host interfaces
+// and returns the changes from the current CIDR without applying them
+func (h *CIDRHandler) PlanCIDR(current multinicv1.CIDRSpec, def multinicv1.PluginConfig) (multinicv1.CIDRPlan, error) {
+	newSpec, err := h.NewCIDR(def, "")
+	if err != nil {
+		return multinicv1.CIDRPlan{}, err
+	}
+	excludes := compute.SortAddress(def.ExcludeCIDRs)
+	entriesMap, _ := h.UpdateEntries(*newSpec.DeepCopy(), excludes, true)
+	plannedEntries := []multinicv1.CIDREntry{}
+	for _, entry := range entriesMap {
+		plannedEntries = append(plannedEntries, entry)
+	}
+	planned := multinicv1.CIDRSpec{Config: def, CIDRs: plannedEntries}
+	plan := diffCIDR(current, planned, h.IPPoolHandler.ListCache(), h.IsL3Mode(current.Config) || h.IsL3Mode(def))
+	return plan, nil
+}
+
+// ApplyCIDRPlan updates CIDR with the new config, routes and IPPools follow the CIDR update
+func (h *CIDRHandler) ApplyCIDRPlan(current multinicv1.CIDRSpec, def multinicv1.PluginConfig) error {
+	newSpec, err := h.NewCIDR(def, "")
+	if err != nil {
+		return err
+	}
+	if current.Config.Subnet != def.Subnet {
+		// table of L3 config is looked up by subnet
+		h.DeleteOldRoutes(current)
+	}
+	_, err = h.updateCIDR(newSpec, true)
+	return err
+}
+
+// diffCIDR returns the plan of changes from current to planned CIDR,
+// routes are listed once per destination and apply to all other hosts
+func diffCIDR(current multinicv1.CIDRSpec, planned multinicv1.CIDRSpec, ippoolSnapshot map[string]multinicv1.IPPoolSpec, l3Mode bool) multinicv1.CIDRPlan {
+	plan := multinicv1.CIDRPlan{}
+	currentVlans, currentHosts := getCIDRMaps(current)
+	plannedVlans, plannedHosts := getCIDRMaps(planned)
+	plan.VlanCIDRs = diffMap(currentVlans, plannedVlans)
+	plan.HostBlocks = diffMap(getPodCIDRMap(currentHosts), getPodCIDRMap(plannedHosts))
+
+	currentPools := make(map[string]string)
+	for key, host := range currentHosts {
+		currentPools[getPlannedIPPoolName(current.Config.Name, host)] = key
+	}
+	plannedPools := make(map[string]string)
+	for key, host := range plannedHosts {
+		plannedPools[getPlannedIPPoolName(planned.Config.Name, host)] = key
+	}
+	plan.IPPools = diffMap(currentPools, plannedPools)
+
+	affectedPods := make(map[string]bool)
+	for _, change := range plan.IPPools {
+		if change.Action != PLAN_DELETE {
+			continue
+		}
+		if ippool, found := ippoolSnapshot[change.Name]; found {
+			for _, allocation := range ippool.Allocations {
+				affectedPods[allocation.Namespace+"/"+allocation.Pod] = true
+			}
+		}
+	}
+	for pod := range affectedPods {
+		plan.AffectedPods = append(plan.AffectedPods, pod)
+	}
+	sort.Strings(plan.AffectedPods)
+
+	if l3Mode {
+		currentRoutes := make(map[string]string)
+		for _, host := range currentHosts {
+			currentRoutes[host.PodCIDR] = host.HostIP
+		}
+		plannedRoutes := make(map[string]string)
+		for _, host := range plannedHosts {
+			plannedRoutes[host.PodCIDR] = host.HostIP
+		}
+		plan.Routes = diffMap(currentRoutes, plannedRoutes)
+	}
+	plan.ID = getPlanID(planned.Config, plan)
+	plan.CreatedTime = metav1.Now()
+	return plan
+}
+
+// getCIDRMaps returns VLAN CIDR by network address and host info by host name and network address
+func getCIDRMaps(spec multinicv1.CIDRSpec) (map[string]string, map[string]multinicv1.HostInterfaceInfo) {
+	vlans := make(map[string]string)
+	hosts := make(map[string]multinicv1.HostInterfaceInfo)
+	for _, entry := range spec.CIDRs {
+		vlans[entry.NetAddress] = entry.VlanCIDR
+		for _, host := range entry.Hosts {
+			hosts[host.HostName+"/"+entry.NetAddress] = host
+		}
+	}
+	return vlans, hosts
+}
+
+func getPodCIDRMap(hosts map[string]multinicv1.HostInterfaceInfo) map[string]string {
+	podCIDRs := make(map[string]string)
+	for key, host := range hosts {
+		podCIDRs[key] = host.PodCIDR
+	}
+	return podCIDRs
+}
+
+func getPlannedIPPoolName(defName string, host multinicv1.HostInterfaceInfo) string {
+	if host.IPPool != "" {
+		return host.IPPool
+	}
+	return defName + "-" + strings.ReplaceAll(host.PodCIDR, "/", "-")
+}
+
+// diffMap returns sorted add, delete, and change of values by name
+func diffMap(current map[string]string, planned map[string]string) []multinicv1.PlannedChange {
+	changes := []multinicv1.PlannedChange{}
+	for name, value := range planned {
+		currentValue, found := current[name]
+		if !found {
+			changes = append(changes, multinicv1.PlannedChange{Action: PLAN_ADD, Name: name, To: value})
+		} else if currentValue != value {
+			changes = append(changes, multinicv1.PlannedChange{Action: PLAN_CHANGE, Name: name, From: currentValue, To: value})
+		}
+	}
+	for name, value := range current {
+		if _, found := planned[name]; !found {
+			changes = append(changes, multinicv1.PlannedChange{Action: PLAN_DELETE, Name: name, From: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// getPlanID returns hash of the new config and changes so that the applied plan is the previewed one
+func getPlanID(def multinicv1.PluginConfig, plan multinicv1.CIDRPlan) string {
+	plan.ID = ""
+	plan.CreatedTime = metav1.Time{}
+	h := fnv.New64a()
+	for _, obj := range []interface{}{def, plan} {
+		if jsonBytes, err := json.Marshal(obj); err == nil {
+			h.Write(jsonBytes)
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// logPlan logs summary of the plan
+func logPlan(name string, plan multinicv1.CIDRPlan) {
+	vars.NetworkLog.V(2).Info(fmt.Sprintf("plan %s of %s: %d VLAN CIDR(s), %d host block(s), %d IPPool(s), %d route(s), %d pod(s) affected",
+		plan.ID, name, len(plan.VlanCIDRs), len(plan.HostBlocks), len(plan.IPPools), len(plan.Routes), len(plan.AffectedPods)))
+}
@@ -7,6 +7,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -88,6 +89,11 @@ func (r *ConfigReconciler) getDefaultConfigSpec() multinicv1.ConfigSpec {
 		PodCNIPath:  "/var/lib/kubelet/device-plugins",
 		HostCNIPath: "/var/lib/kubelet/device-plugins",
 	}
+	podResourcesMnt := multinicv1.HostPathMount{
+		Name:        "pod-resources",
+		PodCNIPath:  "/var/lib/kubelet/pod-resources",
+		HostCNIPath: "/var/lib/kubelet/pod-resources",
+	}
 	routeMnt := multinicv1.HostPathMount{
 		Name:        "rt-tables",
 		PodCNIPath:  "/opt/rt_tables",
@@ -98,7 +104,7 @@ func (r *ConfigReconciler) getDefaultConfigSpec() multinicv1.ConfigSpec {
 		PodCNIPath:  "/usr/share/hwdata",
 		HostCNIPath: "/usr/share/hwdata",
 	}
-	hostPathMounts := []multinicv1.HostPathMount{binMnt, devPluginMnt, routeMnt, hwDataMnt}
+	hostPathMounts := []multinicv1.HostPathMount{binMnt, devPluginMnt, podResourcesMnt, routeMnt, hwDataMnt}
 	resources := corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
 			corev1.ResourceCPU:    resource.MustParse("100m"),
@@ -250,6 +256,7 @@ func (r *ConfigReconciler) UpdateConfigBySpec(spec *multinicv1.ConfigSpec) {
 		vars.ConfigLog.Info(fmt.Sprintf("Configure ContextTimeoutMinutes = %d", spec.ContextTimeoutMinutes))
 		vars.ContextTimeout = time.Duration(spec.ContextTimeoutMinutes) * time.Minute
 	}
+	DefaultRouteRollout = spec.RouteRollout
 	if spec.LogLevel >= 1 && spec.LogLevel <= 127 {
 		if !vars.ConfigLog.V(spec.LogLevel).Enabled() {
 			vars.ConfigLog.Info(fmt.Sprintf("Configure LogLevel = %d", spec.LogLevel))
@@ -298,6 +305,17 @@ func (r *ConfigReconciler) newCNIDaemonSet(client *kubernetes.Clientset, name st
 		},
 	}
 	daemonSpec.Env = append(daemonSpec.Env, hostNameVar)
+	// interface filter environment
+	if daemonSpec.InterfaceFilter != nil {
+		if filterBytes, err := json.Marshal(daemonSpec.InterfaceFilter); err == nil {
+			daemonSpec.Env = append(daemonSpec.Env, corev1.EnvVar{
+				Name:  vars.InterfaceFilterKey,
+				Value: string(filterBytes),
+			})
+		} else {
+			vars.ConfigLog.V(2).Info(fmt.Sprintf("Failed to set interface filter: %v", err))
+		}
+	}
 
 	// prepare secret
 	secrets := []corev1.LocalObjectReference{}
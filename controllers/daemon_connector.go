@@ -43,23 +43,61 @@ type DaemonConnector struct {
 
 // L3 Configuration defines request of l3 route configuration
 type L3ConfigRequest struct {
-	Name   string      `json:"name"`
-	Subnet string      `json:"subnet"`
-	Routes []HostRoute `json:"routes"`
-	Force  bool        `json:"force"`
+	Name      string         `json:"name"`
+	Subnet    string         `json:"subnet"`
+	Routes    []HostRoute    `json:"routes"`
+	Force     bool           `json:"force"`
+	RouteMode string         `json:"routeMode,omitempty"`
+	Advertise []string       `json:"advertise,omitempty"`
+	Overlay   *OverlayConfig `json:"overlay,omitempty"`
+	Rails     []RailConfig   `json:"rails,omitempty"`
+}
+
+// RailConfig is an interface index of network with its own table and source rule on VLAN CIDR
+type RailConfig struct {
+	Index         int    `json:"index"`
+	Subnet        string `json:"subnet"`
+	InterfaceName string `json:"iface"`
+}
+
+// OverlayConfig defines overlay devices riding on the masters
+type OverlayConfig struct {
+	Type string `json:"type"`
+	Port int    `json:"port,omitempty"`
+	// VNIs maps master interface name to VNI of its overlay device
+	VNIs map[string]int `json:"vnis"`
 }
 
 // HostRoute defines a route
 type HostRoute struct {
-	Subnet        string `json:"net"`
+	Subnet        string    `json:"net"`
+	NextHop       string    `json:"via"`
+	InterfaceName string    `json:"iface"`
+	NextHops      []NextHop `json:"nexthops,omitempty"`
+}
+
+// NextHop defines a weighted path of multipath route
+type NextHop struct {
 	NextHop       string `json:"via"`
 	InterfaceName string `json:"iface"`
+	Weight        int    `json:"weight,omitempty"`
 }
 
 // RouteUpdateResponse defines response from adding/deleting routes
 type RouteUpdateResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"msg"`
+	Success bool           `json:"success"`
+	Message string         `json:"msg"`
+	Routes  []RouteOutcome `json:"routes,omitempty"`
+}
+
+// RouteOutcome defines outcome of reconciling a single route on daemon
+type RouteOutcome struct {
+	Subnet        string `json:"net"`
+	NextHop       string `json:"via,omitempty"`
+	InterfaceName string `json:"iface,omitempty"`
+	Action        string `json:"action"`
+	Success       bool   `json:"success"`
+	Error         string `json:"error,omitempty"`
 }
 
 // IPAMInfo defines information about HostInterface sent to daemon for greeting
@@ -125,6 +163,23 @@ func (dc DaemonConnector) ApplyL3Config(podAddress string, cidrName string, subn
 	return dc.putRouteRequest(podAddress, ADD_ROUTE_PATH, cidrName, subnet, routes, forceDelete)
 }
 
+// ApplyL3ConfigRequest sends a request with overlay devices or rails to specific host
+func (dc DaemonConnector) ApplyL3ConfigRequest(podAddress string, requestL3Config L3ConfigRequest) (RouteUpdateResponse, error) {
+	return dc.postL3ConfigRequest(podAddress+ADD_ROUTE_PATH, requestL3Config)
+}
+
+// AdvertiseL3Config sends a request to announce pod CIDRs of specific host by its BGP speaker
+func (dc DaemonConnector) AdvertiseL3Config(podAddress string, cidrName string, subnet string, prefixes []string) (RouteUpdateResponse, error) {
+	requestL3Config := L3ConfigRequest{
+		Name:      cidrName,
+		Subnet:    subnet,
+		Routes:    []HostRoute{},
+		RouteMode: ROUTE_MODE_BGP,
+		Advertise: prefixes,
+	}
+	return dc.postL3ConfigRequest(podAddress+ADD_ROUTE_PATH, requestL3Config)
+}
+
 // DeleteRoute sends a request to delete the route from specific host
 func (dc DaemonConnector) DeleteL3Config(podAddress string, cidrName string, subnet string) (RouteUpdateResponse, error) {
 	return dc.putRouteRequest(podAddress, DELETE_ROUTE_PATH, cidrName, subnet, []HostRoute{}, false)
@@ -132,16 +187,18 @@ func (dc DaemonConnector) DeleteL3Config(podAddress string, cidrName string, sub
 
 // putRouteRequest sends a route adding/deleting request to specific host
 func (dc DaemonConnector) putRouteRequest(podAddress string, path string, cidrName string, subnet string, routes []HostRoute, forceDelete bool) (RouteUpdateResponse, error) {
-	address := podAddress + path
-	var response RouteUpdateResponse
-
 	requestL3Config := L3ConfigRequest{
 		Name:   cidrName,
 		Subnet: subnet,
 		Routes: routes,
 		Force:  forceDelete,
 	}
+	return dc.postL3ConfigRequest(podAddress+path, requestL3Config)
+}
 
+// postL3ConfigRequest posts L3 config request to daemon address
+func (dc DaemonConnector) postL3ConfigRequest(address string, requestL3Config L3ConfigRequest) (RouteUpdateResponse, error) {
+	var response RouteUpdateResponse
 	jsonReq, err := json.Marshal(requestL3Config)
 
 	if err != nil {
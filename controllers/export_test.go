@@ -50,3 +50,39 @@ func (h *IPPoolHandler) CheckPoolValidity(excludeCIDRs []string, allocations []m
 func (h *IPPoolHandler) ExtractMatchExcludesFromPodCIDR(excludes []compute.IPValue, podCIDR string) []string {
 	return h.extractMatchExcludesFromPodCIDR(excludes, podCIDR)
 }
+
+func GetMultipathRoute(config multinicv1.PluginConfig, net string, srcInfoMap map[int]multinicv1.HostInterfaceInfo, destInfoMap map[int]multinicv1.HostInterfaceInfo, netAddresses map[int]string) (HostRoute, bool) {
+	return getMultipathRoute(config, net, srcInfoMap, destInfoMap, netAddresses)
+}
+
+func GetOverlayConfig(config multinicv1.PluginConfig, srcInfoMap map[int]multinicv1.HostInterfaceInfo) *OverlayConfig {
+	return getOverlayConfig(config, srcInfoMap)
+}
+
+func IsSameConfig(config multinicv1.PluginConfig, cmpConfig multinicv1.PluginConfig) bool {
+	return isSameConfig(config, cmpConfig)
+}
+
+func DiffCIDR(current multinicv1.CIDRSpec, planned multinicv1.CIDRSpec, ippoolSnapshot map[string]multinicv1.IPPoolSpec, l3Mode bool) multinicv1.CIDRPlan {
+	return diffCIDR(current, planned, ippoolSnapshot, l3Mode)
+}
+
+func GetRolloutStages(hostNames []string, policy *multinicv1.RolloutPolicy) [][][]string {
+	return getRolloutStages(hostNames, policy)
+}
+
+func IsRolloutHalted(rollout multinicv1.RolloutStatus, policy *multinicv1.RolloutPolicy) bool {
+	return isRolloutHalted(rollout, policy)
+}
+
+func GetRails(entries []multinicv1.CIDREntry, srcInfoMap map[int]multinicv1.HostInterfaceInfo) []RailConfig {
+	return getRails(entries, srcInfoMap)
+}
+
+func IsSameAttributes(olds []multinicv1.InterfaceInfoType, news []multinicv1.InterfaceInfoType) bool {
+	return isSameAttributes(olds, news)
+}
+
+func GetMasterAddress(iface multinicv1.InterfaceInfoType, masterNetAddrs map[string]bool) (multinicv1.InterfaceAddress, bool) {
+	return getMasterAddress(iface, masterNetAddrs)
+}
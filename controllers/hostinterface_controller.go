@@ -8,6 +8,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -136,6 +137,14 @@ func (r *HostInterfaceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	vars.HifLog.V(7).Info(fmt.Sprintf("HostInterface reconciled: %s", instance.ObjectMeta.Name))
+	if cachedInstance, err := r.HostInterfaceHandler.GetCache(hifName); err == nil && !isSameAttributes(cachedInstance.Spec.Interfaces, instance.Spec.Interfaces) {
+		// interfaces written by daemon on link and address updates
+		r.HostInterfaceHandler.SetCache(hifName, *instance.DeepCopy())
+		if !IsSameInterfaces(cachedInstance.Spec.Interfaces, instance.Spec.Interfaces) {
+			vars.HifLog.V(4).Info(fmt.Sprintf("%s's interfaces changed: %d to %d", hifName, len(cachedInstance.Spec.Interfaces), len(instance.Spec.Interfaces)))
+			r.CIDRHandler.UpdateCIDRs()
+		}
+	}
 	err = r.UpdateInterfaces(*instance)
 	if err != nil {
 		// deamon pod may be missing for a short time
@@ -164,11 +173,11 @@ func (r *HostInterfaceReconciler) UpdateInterfaces(instance multinicv1.HostInter
 			return fmt.Errorf(vars.ThrottlingError)
 		}
 		podAddress := GetDaemonAddressByPod(pod)
-		interfaces, err := r.DaemonWatcher.DaemonConnector.GetInterfaces(podAddress)
+		newInterfaces, err := r.DaemonWatcher.DaemonConnector.GetInterfaces(podAddress)
 		if err != nil {
 			return err
 		}
-		interfaces, updated := UpdateNewInterfaces(instance.Spec.Interfaces, interfaces)
+		interfaces, updated := UpdateNewInterfaces(instance.Spec.Interfaces, newInterfaces)
 		if updated {
 			err = r.DaemonWatcher.IpamJoin(pod)
 			if err != nil {
@@ -181,6 +190,13 @@ func (r *HostInterfaceReconciler) UpdateInterfaces(instance multinicv1.HostInter
 			vars.HifLog.V(7).Info(fmt.Sprintf("%s's interfaces updated", nodeName))
 			r.HostInterfaceHandler.SetCache(hifName, *updatedHif.DeepCopy())
 			r.CIDRHandler.UpdateCIDRs()
+		} else if len(newInterfaces) > 0 && !isSameAttributes(instance.Spec.Interfaces, newInterfaces) {
+			// only link attributes such as speed and carrier changed, CIDR is not affected
+			updatedHif, err := r.HostInterfaceHandler.UpdateHostInterface(instance, newInterfaces)
+			if err != nil {
+				return err
+			}
+			r.HostInterfaceHandler.SetCache(hifName, *updatedHif.DeepCopy())
 		}
 		return nil
 	}
@@ -212,36 +228,50 @@ func (r *HostInterfaceReconciler) UpdateInterfaces(instance multinicv1.HostInter
 	}
 }
 
+// UpdateNewInterfaces returns interfaces from daemon if changed, interfaces missing from daemon are removed,
+// empty interfaces are ignored since daemon may not be ready
 func UpdateNewInterfaces(olds []multinicv1.InterfaceInfoType, news []multinicv1.InterfaceInfoType) ([]multinicv1.InterfaceInfoType, bool) {
 	if len(news) == 0 {
 		return olds, false
 	}
-	updated := false
+	if IsSameInterfaces(olds, news) {
+		return olds, false
+	}
+	return news, true
+}
+
+// IsSameInterfaces checks if both have the same interfaces regardless of order
+func IsSameInterfaces(olds []multinicv1.InterfaceInfoType, news []multinicv1.InterfaceInfoType) bool {
+	if len(olds) != len(news) {
+		return false
+	}
 	oldMap := make(map[string]multinicv1.InterfaceInfoType)
 	for _, old := range olds {
 		oldMap[old.InterfaceName] = old
 	}
 	for _, new := range news {
-		if old, exists := oldMap[new.InterfaceName]; exists {
-			if !old.Equal(new) {
-				updated = true
-			}
-			delete(oldMap, new.InterfaceName)
-		} else {
-			updated = true
+		if old, exists := oldMap[new.InterfaceName]; !exists || !old.Equal(new) {
+			return false
 		}
 	}
-	if !updated {
-		return olds, false
+	return true
+}
+
+// isSameAttributes checks if both have the same interfaces with all link attributes regardless of order
+func isSameAttributes(olds []multinicv1.InterfaceInfoType, news []multinicv1.InterfaceInfoType) bool {
+	if len(olds) != len(news) {
+		return false
 	}
-	if len(oldMap) == 0 {
-		// no missing old interfaces
-		return news, updated
+	oldMap := make(map[string]multinicv1.InterfaceInfoType)
+	for _, old := range olds {
+		oldMap[old.InterfaceName] = old
 	}
-	for _, old := range oldMap {
-		news = append(news, old)
+	for _, new := range news {
+		if old, exists := oldMap[new.InterfaceName]; !exists || !reflect.DeepEqual(old, new) {
+			return false
+		}
 	}
-	return news, true
+	return true
 }
 
 // CallFinalizer updates CIDRs
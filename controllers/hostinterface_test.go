@@ -41,17 +41,15 @@ var _ = Describe("Host Interface Test", func() {
 			_, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
 			Expect(updated).To(BeFalse())
 		})
-		It("can add new while leave old one", func() {
+		It("can add new and remove renamed one", func() {
 			newInfos := []multinicv1.InterfaceInfoType{
 				genInterfaceInfo("eth2", "10.0.1.0/24"),
 			}
 			newInfos, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
 			Expect(updated).To(BeTrue())
-			Expect(len(newInfos)).To(Equal(2))
-			for _, newInfo := range newInfos {
-				Expect(newInfo.InterfaceName).To(BeElementOf("eth1", "eth2"))
-				Expect(newInfo.NetAddress).To(BeElementOf("10.0.0.0/24", "10.0.1.0/24"))
-			}
+			Expect(len(newInfos)).To(Equal(1))
+			Expect(newInfos[0].InterfaceName).To(Equal("eth2"))
+			Expect(newInfos[0].NetAddress).To(Equal("10.0.1.0/24"))
 		})
 	})
 	Context("UpdateNewInterfaces - original with more than one devices", func() {
@@ -80,27 +78,44 @@ var _ = Describe("Host Interface Test", func() {
 			_, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
 			Expect(updated).To(BeFalse())
 		})
-		It("can leave old one when some is missing", func() {
+		It("can remove missing one", func() {
 			newInfos := []multinicv1.InterfaceInfoType{
 				genInterfaceInfo("eth1", "10.0.0.0/24"),
 			}
-			_, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
-			Expect(updated).To(BeFalse())
+			newInfos, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
+			Expect(updated).To(BeTrue())
+			Expect(len(newInfos)).To(Equal(1))
+			Expect(newInfos[0].InterfaceName).To(Equal("eth1"))
 		})
-		It("can leave old one when some is missing and some with new info", func() {
+		It("can remove missing one and update readdressed one", func() {
 			newInfos := []multinicv1.InterfaceInfoType{
 				genInterfaceInfo("eth1", "10.0.2.0/24"),
 			}
 			newInfos, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
 			Expect(updated).To(BeTrue())
-			Expect(len(newInfos)).To(Equal(2))
-			for _, newInfo := range newInfos {
-				Expect(newInfo.InterfaceName).To(BeElementOf("eth1", "eth2"))
-				Expect(newInfo.NetAddress).To(BeElementOf("10.0.2.0/24", "10.0.1.0/24"))
-			}
+			Expect(len(newInfos)).To(Equal(1))
+			Expect(newInfos[0].InterfaceName).To(Equal("eth1"))
+			Expect(newInfos[0].NetAddress).To(Equal("10.0.2.0/24"))
 		})
 	})
 
+	Context("Link attributes", func() {
+		It("detects attribute change without CIDR change", func() {
+			origInfos := []multinicv1.InterfaceInfoType{
+				genInterfaceInfo("eth1", "10.0.0.0/24"),
+			}
+			newInfos := []multinicv1.InterfaceInfoType{
+				genInterfaceInfo("eth1", "10.0.0.0/24"),
+			}
+			newInfos[0].Speed = 100000
+			newInfos[0].Carrier = true
+			newInfos[0].NumaNode = "0"
+			_, updated := controllers.UpdateNewInterfaces(origInfos, newInfos)
+			Expect(updated).To(BeFalse())
+			Expect(controllers.IsSameAttributes(origInfos, newInfos)).To(BeFalse())
+			Expect(controllers.IsSameAttributes(newInfos, newInfos)).To(BeTrue())
+		})
+	})
 })
 
 func genInterfaceInfo(devName, netAddress string) multinicv1.InterfaceInfoType {
@@ -277,10 +277,11 @@ func (r *MultiNicNetworkReconciler) HandleMultiNicIPAM(instance *multinicv1.Mult
 	}
 	if err == nil {
 		cidrName := instance.GetName()
-		_, err := r.CIDRHandler.GetCIDR(cidrName)
-		// create new cidr if not created yet. otherwise, let cidr controller update
+		cidr, err := r.CIDRHandler.GetCIDR(cidrName)
+		// create new cidr if not created yet. otherwise, plan or apply the config change
 		if err == nil {
 			vars.NetworkLog.V(3).Info(fmt.Sprintf("CIDR %s already exists", cidrName))
+			return r.handleIPAMConfigChange(instance, cidr.Spec, *ipamConfig)
 		} else {
 			if errors.IsNotFound(err) {
 				_, err = r.CIDRHandler.NewCIDRWithNewConfig(*ipamConfig, instance.GetNamespace())
@@ -294,6 +295,40 @@ func (r *MultiNicNetworkReconciler) HandleMultiNicIPAM(instance *multinicv1.Mult
 	return err
 }
 
+// handleIPAMConfigChange shows the changes of new IPAM config as plan in status
+// and applies the plan only when its ID is set to the apply-plan annotation
+func (r *MultiNicNetworkReconciler) handleIPAMConfigChange(instance *multinicv1.MultiNicNetwork, current multinicv1.CIDRSpec, def multinicv1.PluginConfig) error {
+	handler := r.CIDRHandler.MultiNicNetworkHandler
+	if isSameConfig(current.Config, def) {
+		if instance.Status.Plan != nil {
+			instance.Status.Plan = nil
+			return handler.UpdateNetConfigStatus(instance, instance.Status.NetConfigStatus, "")
+		}
+		return nil
+	}
+	plan, err := r.CIDRHandler.PlanCIDR(current, def)
+	if err != nil {
+		return err
+	}
+	if instance.GetAnnotations()[APPLY_PLAN_ANNOTATION] == plan.ID {
+		vars.NetworkLog.V(2).Info(fmt.Sprintf("Apply plan %s to %s", plan.ID, instance.Name))
+		err = r.CIDRHandler.ApplyCIDRPlan(current, def)
+		if err != nil {
+			return err
+		}
+		instance.Status.Plan = nil
+		return handler.UpdateNetConfigStatus(instance, multinicv1.WaitForConfig, fmt.Sprintf("plan %s applied", plan.ID))
+	}
+	if instance.Status.Plan != nil && instance.Status.Plan.ID == plan.ID {
+		// plan not changed
+		return nil
+	}
+	logPlan(instance.Name, plan)
+	instance.Status.Plan = &plan
+	message := fmt.Sprintf("plan %s not applied, set annotation %s=%s to apply", plan.ID, APPLY_PLAN_ANNOTATION, plan.ID)
+	return handler.UpdateNetConfigStatus(instance, instance.Status.NetConfigStatus, message)
+}
+
 // callFinalizer deletes NetworkAttachmentDefinition, CIDR and its dependencies
 func (r *MultiNicNetworkReconciler) callFinalizer(reqLogger logr.Logger, instance *multinicv1.MultiNicNetwork) error {
 	isMultiNicIPAM, err := IsMultiNICIPAM(instance)
@@ -111,6 +111,8 @@ func (h *MultiNicNetworkHandler) updateStatus(instance *multinicv1.MultiNicNetwo
 		NetConfigStatus: netConfigStatus,
 		Message:         message,
 		RouteStatus:     status,
+		Plan:            instance.Status.Plan,
+		Rollout:         instance.Status.Rollout,
 	}
 
 	if !NetStatusUpdated(instance, netStatus) {
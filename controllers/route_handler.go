@@ -7,35 +7,80 @@ package controllers
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 
 	multinicv1 "github.com/foundation-model-stack/multi-nic-cni/api/v1"
 	"github.com/foundation-model-stack/multi-nic-cni/internal/vars"
 )
 
+const (
+	ROUTE_MODE_STATIC = "static"
+	ROUTE_MODE_BGP    = "bgp"
+
+	OVERLAY_VXLAN       = "vxlan"
+	DEFAULT_OVERLAY_VNI = 4096
+)
+
 // RouteHandler handles routes according to CIDR by connecting DaemonConnector
 type RouteHandler struct {
 	DaemonConnector
 	*DaemonCacheHandler
 }
 
-// AddRoutes add corresponding routes of CIDR
+// AddRoutes add corresponding routes of CIDR in stages of the rollout policy
 // success: all routes is properly updated
-func (h *RouteHandler) AddRoutes(cidrSpec multinicv1.CIDRSpec, entries []multinicv1.CIDREntry, hostInterfaceInfoMap map[string]map[int]multinicv1.HostInterfaceInfo, forceDelete bool) (success bool, noConnection bool) {
+func (h *RouteHandler) AddRoutes(cidrSpec multinicv1.CIDRSpec, entries []multinicv1.CIDREntry, hostInterfaceInfoMap map[string]map[int]multinicv1.HostInterfaceInfo, forceDelete bool, policy *multinicv1.RolloutPolicy) (success bool, noConnection bool, rollout multinicv1.RolloutStatus) {
 	success = true
 	noConnection = false
 	daemonCache := h.DaemonCacheHandler.ListCache()
-	for hostName, daemon := range daemonCache {
+	hostNames := []string{}
+	for hostName := range daemonCache {
 		if _, ok := hostInterfaceInfoMap[hostName]; ok {
-			change, connectFail := h.AddRoutesToHost(cidrSpec, hostName, daemon, entries, hostInterfaceInfoMap, forceDelete)
-			if !change || connectFail {
-				success = false
+			hostNames = append(hostNames, hostName)
+		}
+	}
+	sort.Strings(hostNames)
+	rollout = multinicv1.RolloutStatus{Total: len(hostNames)}
+	var mutex sync.Mutex
+	for _, stage := range getRolloutStages(hostNames, policy) {
+		for _, batch := range stage {
+			var wg sync.WaitGroup
+			for _, hostName := range batch {
+				wg.Add(1)
+				go func(hostName string) {
+					defer wg.Done()
+					change, connectFail := h.AddRoutesToHost(cidrSpec, hostName, daemonCache[hostName], entries, hostInterfaceInfoMap, forceDelete)
+					mutex.Lock()
+					defer mutex.Unlock()
+					rollout.Updated += 1
+					if !change || connectFail {
+						success = false
+						rollout.Failed += 1
+						rollout.FailedHosts = append(rollout.FailedHosts, hostName)
+					}
+					if connectFail {
+						noConnection = true
+					}
+				}(hostName)
 			}
-			if connectFail {
-				noConnection = true
+			wg.Wait()
+			if isRolloutHalted(rollout, policy) {
+				rollout.Halted = true
+				rollout.Message = fmt.Sprintf("halted: %d of %d updated nodes failed", rollout.Failed, rollout.Updated)
+				sort.Strings(rollout.FailedHosts)
+				return success, noConnection, rollout
 			}
 		}
+		if policy != nil && policy.CanaryPercent > 0 && rollout.Failed > 0 {
+			rollout.Halted = true
+			rollout.Message = fmt.Sprintf("halted: %d of %d canary nodes failed", rollout.Failed, rollout.Updated)
+			sort.Strings(rollout.FailedHosts)
+			return success, noConnection, rollout
+		}
 	}
-	return success, noConnection
+	sort.Strings(rollout.FailedHosts)
+	return success, noConnection, rollout
 }
 
 // AddRoutesToHost add route to a specific host
@@ -46,9 +91,16 @@ func (h *RouteHandler) AddRoutesToHost(cidrSpec multinicv1.CIDRSpec, hostName st
 		// no change, connecion failed
 		return false, true
 	}
+	if cidrSpec.Config.RouteMode == ROUTE_MODE_BGP {
+		return h.advertiseRoutesFromHost(cidrSpec, hostName, daemon, entries)
+	}
 	change := true
 	mainSrcHostIP := daemon.HostIP
 	routes := []HostRoute{}
+	netAddresses := make(map[int]string)
+	for _, entry := range entries {
+		netAddresses[entry.InterfaceIndex] = entry.NetAddress
+	}
 	for _, entry := range entries {
 		interfaceIndex := entry.InterfaceIndex
 		for _, host := range entry.Hosts {
@@ -60,7 +112,11 @@ func (h *RouteHandler) AddRoutesToHost(cidrSpec multinicv1.CIDRSpec, hostName st
 			}
 			mainDestHostIP := destDaemon.HostIP
 			net := host.PodCIDR
-			if mainDestHostIP != mainSrcHostIP {
+			if mainDestHostIP != mainSrcHostIP && cidrSpec.Config.Multipath {
+				if route, found := getMultipathRoute(cidrSpec.Config, net, hostInterfaceInfoMap[hostName], hostInterfaceInfoMap[destHostName], netAddresses); found {
+					routes = append(routes, route)
+				}
+			} else if mainDestHostIP != mainSrcHostIP {
 				if ifaceInfo, exist := hostInterfaceInfoMap[hostName][interfaceIndex]; exist {
 					iface := ifaceInfo.InterfaceName
 					via := hostInterfaceInfoMap[destHostName][interfaceIndex].HostIP
@@ -75,11 +131,33 @@ func (h *RouteHandler) AddRoutesToHost(cidrSpec multinicv1.CIDRSpec, hostName st
 		}
 	}
 	podAddress := GetDaemonAddressByPod(daemon)
-	res, err := h.DaemonConnector.ApplyL3Config(podAddress, cidrSpec.Config.Name, cidrSpec.Config.Subnet, routes, forceDelete)
+	var res RouteUpdateResponse
+	if cidrSpec.Config.VlanMode == OVERLAY_VXLAN || cidrSpec.Config.SourceRouting {
+		requestL3Config := L3ConfigRequest{
+			Name:   cidrSpec.Config.Name,
+			Subnet: cidrSpec.Config.Subnet,
+			Routes: routes,
+			Force:  forceDelete,
+		}
+		if cidrSpec.Config.VlanMode == OVERLAY_VXLAN {
+			requestL3Config.Overlay = getOverlayConfig(cidrSpec.Config, hostInterfaceInfoMap[hostName])
+		}
+		if cidrSpec.Config.SourceRouting {
+			requestL3Config.Rails = getRails(entries, hostInterfaceInfoMap[hostName])
+		}
+		res, err = h.DaemonConnector.ApplyL3ConfigRequest(podAddress, requestL3Config)
+	} else {
+		res, err = h.DaemonConnector.ApplyL3Config(podAddress, cidrSpec.Config.Name, cidrSpec.Config.Subnet, routes, forceDelete)
+	}
 	if err != nil {
 		vars.CIDRLog.V(6).Info(fmt.Sprintf("fail to apply L3config %s to %s: %v (%v)", cidrSpec.Config.Name, hostName, res, err))
 	} else {
-		vars.CIDRLog.V(6).Info(fmt.Sprintf("Apply L3config %s to %s: %v", cidrSpec.Config.Name, hostName, res.Success))
+		vars.CIDRLog.V(6).Info(fmt.Sprintf("Apply L3config %s to %s: %v (%s)", cidrSpec.Config.Name, hostName, res.Success, res.Message))
+		for _, outcome := range res.Routes {
+			if !outcome.Success {
+				vars.CIDRLog.V(4).Info(fmt.Sprintf("failed to %s route %s via %s on %s at %s: %s", outcome.Action, outcome.Subnet, outcome.NextHop, outcome.InterfaceName, hostName, outcome.Error))
+			}
+		}
 	}
 	if err != nil || !res.Success {
 		change = false
@@ -87,6 +165,94 @@ func (h *RouteHandler) AddRoutesToHost(cidrSpec multinicv1.CIDRSpec, hostName st
 	return change, res.Message == vars.ConnectionRefusedError
 }
 
+// getOverlayConfig returns VXLAN device per master of the host, VNI is the same for the interface index on all hosts
+func getOverlayConfig(config multinicv1.PluginConfig, srcInfoMap map[int]multinicv1.HostInterfaceInfo) *OverlayConfig {
+	baseVNI := config.OverlayVNI
+	if baseVNI == 0 {
+		baseVNI = DEFAULT_OVERLAY_VNI
+	}
+	vnis := make(map[string]int)
+	for interfaceIndex, ifaceInfo := range srcInfoMap {
+		vnis[ifaceInfo.InterfaceName] = baseVNI + interfaceIndex
+	}
+	return &OverlayConfig{
+		Type: OVERLAY_VXLAN,
+		Port: config.OverlayPort,
+		VNIs: vnis,
+	}
+}
+
+// getRails returns table and source rule per interface index of the host keyed on VLAN CIDR
+func getRails(entries []multinicv1.CIDREntry, srcInfoMap map[int]multinicv1.HostInterfaceInfo) []RailConfig {
+	rails := []RailConfig{}
+	for _, entry := range entries {
+		if ifaceInfo, exist := srcInfoMap[entry.InterfaceIndex]; exist && entry.VlanCIDR != "" {
+			rails = append(rails, RailConfig{
+				Index:         entry.InterfaceIndex,
+				Subnet:        entry.VlanCIDR,
+				InterfaceName: ifaceInfo.InterfaceName,
+			})
+		}
+	}
+	sort.Slice(rails, func(i, j int) bool {
+		return rails[i].Index < rails[j].Index
+	})
+	return rails
+}
+
+// advertiseRoutesFromHost lets daemon of the host announce its own pod CIDRs instead of pushing routes to all other hosts
+func (h *RouteHandler) advertiseRoutesFromHost(cidrSpec multinicv1.CIDRSpec, hostName string, daemon DaemonPod, entries []multinicv1.CIDREntry) (bool, bool) {
+	prefixes := []string{}
+	for _, entry := range entries {
+		for _, host := range entry.Hosts {
+			if host.HostName == hostName {
+				prefixes = append(prefixes, host.PodCIDR)
+			}
+		}
+	}
+	podAddress := GetDaemonAddressByPod(daemon)
+	res, err := h.DaemonConnector.AdvertiseL3Config(podAddress, cidrSpec.Config.Name, cidrSpec.Config.Subnet, prefixes)
+	if err != nil {
+		vars.CIDRLog.V(6).Info(fmt.Sprintf("fail to advertise L3config %s from %s: %v (%v)", cidrSpec.Config.Name, hostName, res, err))
+	} else {
+		vars.CIDRLog.V(6).Info(fmt.Sprintf("Advertise L3config %s from %s: %v (%s)", cidrSpec.Config.Name, hostName, res.Success, res.Message))
+	}
+	return err == nil && res.Success, res.Message == vars.ConnectionRefusedError
+}
+
+// getMultipathRoute returns a route to the destination pod CIDR over all interface indexes shared by source and destination hosts
+func getMultipathRoute(config multinicv1.PluginConfig, net string, srcInfoMap map[int]multinicv1.HostInterfaceInfo, destInfoMap map[int]multinicv1.HostInterfaceInfo, netAddresses map[int]string) (HostRoute, bool) {
+	interfaceIndexes := []int{}
+	for interfaceIndex := range srcInfoMap {
+		if _, exist := destInfoMap[interfaceIndex]; exist {
+			interfaceIndexes = append(interfaceIndexes, interfaceIndex)
+		}
+	}
+	sort.Ints(interfaceIndexes)
+	nextHops := []NextHop{}
+	for _, interfaceIndex := range interfaceIndexes {
+		weight := 1
+		if setWeight, found := config.MultipathWeights[netAddresses[interfaceIndex]]; found {
+			weight = setWeight
+		}
+		if weight <= 0 {
+			continue
+		}
+		nextHops = append(nextHops, NextHop{
+			NextHop:       destInfoMap[interfaceIndex].HostIP,
+			InterfaceName: srcInfoMap[interfaceIndex].InterfaceName,
+			Weight:        weight,
+		})
+	}
+	switch len(nextHops) {
+	case 0:
+		return HostRoute{}, false
+	case 1:
+		return HostRoute{Subnet: net, NextHop: nextHops[0].NextHop, InterfaceName: nextHops[0].InterfaceName}, true
+	}
+	return HostRoute{Subnet: net, NextHops: nextHops}, true
+}
+
 // DeleteRoutes deletes corresponding routes of CIDR
 func (h *RouteHandler) DeleteRoutes(cidrSpec multinicv1.CIDRSpec) {
 	daemonCache := h.DaemonCacheHandler.ListCache()
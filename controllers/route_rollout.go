@@ -0,0 +1,92 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	multinicv1 "github.com/foundation-model-stack/multi-nic-cni/api/v1"
+	"github.com/foundation-model-stack/multi-nic-cni/internal/vars"
+)
+
+// DefaultRouteRollout is the rollout policy from Config for networks without their own policy
+var DefaultRouteRollout *multinicv1.RolloutPolicy
+
+// getRolloutStages splits hosts into canary and remaining stages,
+// each stage is split into batches of hosts updated at the same time
+func getRolloutStages(hostNames []string, policy *multinicv1.RolloutPolicy) [][][]string {
+	if policy == nil {
+		// one by one, as without policy
+		return [][][]string{splitBatches(hostNames, 1)}
+	}
+	maxConcurrent := policy.MaxConcurrent
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	canarySize := 0
+	if policy.CanaryPercent > 0 {
+		// at least one canary node
+		canarySize = (len(hostNames)*policy.CanaryPercent + 99) / 100
+	}
+	if canarySize == 0 || canarySize >= len(hostNames) {
+		return [][][]string{splitBatches(hostNames, maxConcurrent)}
+	}
+	return [][][]string{
+		splitBatches(hostNames[:canarySize], maxConcurrent),
+		splitBatches(hostNames[canarySize:], maxConcurrent),
+	}
+}
+
+func splitBatches(hostNames []string, size int) [][]string {
+	batches := [][]string{}
+	for start := 0; start < len(hostNames); start += size {
+		end := start + size
+		if end > len(hostNames) {
+			end = len(hostNames)
+		}
+		batches = append(batches, hostNames[start:end])
+	}
+	return batches
+}
+
+// isRolloutHalted checks if failed nodes exceed maxFailurePercent of the updated nodes
+func isRolloutHalted(rollout multinicv1.RolloutStatus, policy *multinicv1.RolloutPolicy) bool {
+	if policy == nil || policy.MaxFailurePercent == nil || rollout.Updated == 0 {
+		return false
+	}
+	return rollout.Failed*100 > *policy.MaxFailurePercent*rollout.Updated
+}
+
+// getRolloutPolicy returns rollout policy of the network, or of Config if not set
+func (h *CIDRHandler) getRolloutPolicy(name string) *multinicv1.RolloutPolicy {
+	if net, err := h.MultiNicNetworkHandler.GetNetwork(name); err == nil && net.Spec.RouteRollout != nil {
+		return net.Spec.RouteRollout
+	}
+	return DefaultRouteRollout
+}
+
+// UpdateRolloutStatus sets progress of route rollout to the network status if changed
+func (h *MultiNicNetworkHandler) UpdateRolloutStatus(name string, rollout *multinicv1.RolloutStatus) error {
+	instance, err := h.GetNetwork(name)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(instance.Status.Rollout, rollout) {
+		return nil
+	}
+	instance.Status.Rollout = rollout
+	ctx, cancel := context.WithTimeout(context.Background(), vars.ContextTimeout)
+	defer cancel()
+	err = h.Client.Status().Update(ctx, instance)
+	if err != nil {
+		vars.NetworkLog.V(2).Info(fmt.Sprintf("Failed to update rollout status of %s: %v", name, err))
+	} else {
+		h.SetCache(instance.Name, *instance)
+	}
+	return err
+}
@@ -18,6 +18,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/apierror"
 	"github.com/foundation-model-stack/multi-nic-cni/daemon/backend"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -207,12 +208,28 @@ func GenerateAllocateIndexes(allocations []backend.Allocation, maxIndex int, exc
 	return indexes
 }
 
-func AllocateIP(req IPRequest) []IPResponse {
+// getMissingInterfaces returns requested interfaces without response
+func getMissingInterfaces(interfaceNames []string, responses []IPResponse) []string {
+	responded := make(map[string]bool)
+	for _, response := range responses {
+		responded[response.InterfaceName] = true
+	}
+	missing := []string{}
+	for _, interfaceName := range interfaceNames {
+		if !responded[interfaceName] {
+			missing = append(missing, interfaceName)
+		}
+	}
+	return missing
+}
+
+func AllocateIP(req IPRequest) ([]IPResponse, error) {
 	podName := req.PodName
 	podNamespace := req.PodNamespace
 	defName := req.NetAttachDefName
 	hostName := req.HostName
-	interfaceNames := req.InterfaceNames
+	// allocateIP removes matched interface from the list
+	interfaceNames := append([]string{}, req.InterfaceNames...)
 
 	FlushExpiredHistory()
 	offset := 1
@@ -234,7 +251,12 @@ func AllocateIP(req IPRequest) []IPResponse {
 	if err != nil || len(ippoolSpecMap) == 0 {
 		log.Printf("Unable to proceed allocation without ippool or with error, ippools: %v, err: %v", ippoolSpecMap, err)
 		allocatorLock.Unlock()
-		return responses
+		if err != nil {
+			return responses, apierror.New(apierror.BackendUnavailable, defName, hostName,
+				fmt.Sprintf("failed to list IPPool: %v", err))
+		}
+		return responses, apierror.New(apierror.IPPoolNotFound, defName, hostName,
+			fmt.Sprintf("no IPPool of network %s on host %s", defName, hostName)).WithMissing(req.InterfaceNames)
 	}
 	newAllocations := allocateIP(podName, podNamespace, interfaceNames, offset, ippoolSpecMap)
 	responses = applyNewAllocations(ippoolSpecMap, newAllocations)
@@ -242,7 +264,11 @@ func AllocateIP(req IPRequest) []IPResponse {
 
 	elapsed := time.Since(startAllocate)
 	log.Println(fmt.Sprintf("Allocate elapsed: %d us", int64(elapsed/time.Microsecond)))
-	return responses
+	if len(responses) == 0 {
+		return responses, apierror.New(apierror.AddressUnavailable, defName, hostName,
+			fmt.Sprintf("no address allocated from %d IPPool(s)", len(ippoolSpecMap))).WithMissing(getMissingInterfaces(req.InterfaceNames, responses))
+	}
+	return responses, nil
 }
 
 func allocateIP(podName, podNamespace string, interfaceNames []string, offset int,
@@ -401,7 +427,7 @@ func CleanHangingAllocation(hostName string) error {
 	return nil
 }
 
-func DeallocateIP(req IPRequest) []IPResponse {
+func DeallocateIP(req IPRequest) ([]IPResponse, error) {
 	podName := req.PodName
 	podNamespace := req.PodNamespace
 	defName := req.NetAttachDefName
@@ -426,7 +452,9 @@ func DeallocateIP(req IPRequest) []IPResponse {
 	}
 	ippoolSpecMap, err := IppoolHandler.ListIPPool(listOptions)
 	if err != nil {
-		return responses
+		allocatorLock.Unlock()
+		return responses, apierror.New(apierror.BackendUnavailable, defName, hostName,
+			fmt.Sprintf("failed to list IPPool: %v", err))
 	}
 	for ippoolName, _ := range ippoolSpecMap {
 		spec := ippoolSpecMap[ippoolName]
@@ -468,7 +496,7 @@ func DeallocateIP(req IPRequest) []IPResponse {
 
 	elapsed := time.Since(startDeallocate)
 	log.Println(fmt.Sprintf("Deallocate elapsed: %d us", int64(elapsed/time.Microsecond)))
-	return responses
+	return responses, nil
 }
 
 func FlushExpiredHistory() {
@@ -239,10 +239,12 @@ spec:
 				InterfaceNames:   []string{interfaceName},
 			}
 			By("Allocating IP")
-			responses := AllocateIP(req)
+			responses, err := AllocateIP(req)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(responses).To(HaveLen(1))
 			By("Deallocating IP")
-			responses = DeallocateIP(req)
+			responses, err = DeallocateIP(req)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(responses).To(HaveLen(1))
 		})
 
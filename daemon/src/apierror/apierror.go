@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package apierror
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Reason defines failure reason code of daemon API
+type Reason string
+
+const (
+	InvalidRequest      Reason = "InvalidRequest"
+	BackendUnavailable  Reason = "BackendUnavailable"
+	NetworkNotFound     Reason = "NetworkNotFound"
+	IPPoolNotFound      Reason = "IPPoolNotFound"
+	AddressUnavailable  Reason = "AddressUnavailable"
+	NoInterfaceSelected Reason = "NoInterfaceSelected"
+)
+
+var reasonStatusMap = map[Reason]int{
+	InvalidRequest:      http.StatusBadRequest,
+	BackendUnavailable:  http.StatusServiceUnavailable,
+	NetworkNotFound:     http.StatusNotFound,
+	IPPoolNotFound:      http.StatusNotFound,
+	AddressUnavailable:  http.StatusConflict,
+	NoInterfaceSelected: http.StatusUnprocessableEntity,
+}
+
+// ErrorResponse is the body of non-OK response from allocate, deallocate, and select
+type ErrorResponse struct {
+	Reason    Reason   `json:"reason"`
+	Message   string   `json:"message"`
+	Network   string   `json:"network,omitempty"`
+	Host      string   `json:"host,omitempty"`
+	Interface string   `json:"interface,omitempty"`
+	Missing   []string `json:"missing,omitempty"`
+}
+
+func New(reason Reason, network, host, message string) *ErrorResponse {
+	return &ErrorResponse{
+		Reason:  reason,
+		Message: message,
+		Network: network,
+		Host:    host,
+	}
+}
+
+// WithMissing sets missing items and the interface if only one is missing
+func (e *ErrorResponse) WithMissing(missing []string) *ErrorResponse {
+	e.Missing = missing
+	if len(missing) == 1 {
+		e.Interface = missing[0]
+	}
+	return e
+}
+
+func (e *ErrorResponse) StatusCode() int {
+	if status, ok := reasonStatusMap[e.Reason]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Reason, e.Message)
+	if len(e.Missing) > 0 {
+		msg = fmt.Sprintf("%s (missing: %s)", msg, strings.Join(e.Missing, ","))
+	}
+	return msg
+}
+
+// Write writes status code and JSON body to response writer
+func (e *ErrorResponse) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(e.StatusCode())
+	json.NewEncoder(w).Encode(e)
+}
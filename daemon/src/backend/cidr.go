@@ -0,0 +1,73 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package backend
+
+import (
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	CIDR_RESOURCE = "cidrs.v1.multinic.fms.io"
+)
+
+type CIDRConfig struct {
+	Name   string `json:"name"`
+	Subnet string `json:"subnet"`
+}
+
+type CIDREntry struct {
+	InterfaceIndex int    `json:"interfaceIndex"`
+	VlanCIDR       string `json:"vlanCIDR"`
+}
+
+type CIDRSpec struct {
+	Config CIDRConfig  `json:"config"`
+	CIDRs  []CIDREntry `json:"cidr"`
+}
+
+type CIDRHandler struct {
+	*DynamicHandler
+}
+
+func NewCIDRHandler(config *rest.Config) *CIDRHandler {
+	dc, _ := discovery.NewDiscoveryClientForConfig(config)
+	dyn, _ := dynamic.NewForConfig(config)
+
+	handler := &CIDRHandler{
+		DynamicHandler: &DynamicHandler{
+			DC:           dc,
+			DYN:          dyn,
+			ResourceName: CIDR_RESOURCE,
+			Kind:         CIDR_KIND,
+		},
+	}
+	return handler
+}
+
+// ListNetworks returns CIDR spec of each network that has CIDR
+func (h *CIDRHandler) ListNetworks() (map[string]CIDRSpec, error) {
+	networks := make(map[string]CIDRSpec)
+	cidrs, err := h.DynamicHandler.List(metav1.NamespaceAll, metav1.ListOptions{})
+	if err != nil {
+		return networks, err
+	}
+	for _, cidr := range cidrs.Items {
+		spec := &CIDRSpec{}
+		if specObj, ok := cidr.Object["spec"].(map[string]interface{}); ok {
+			h.DynamicHandler.Parse(specObj, spec)
+		}
+		name := spec.Config.Name
+		if name == "" {
+			name = cidr.GetName()
+		}
+		networks[name] = *spec
+	}
+	return networks, nil
+}
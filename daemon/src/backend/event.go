@@ -0,0 +1,85 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	CIDR_API_VERSION   = "multinic.fms.io/v1"
+	CIDR_KIND          = "CIDR"
+	EVENT_COMPONENT    = "multi-nic-cni-daemon"
+	RouteRepaired      = "RouteRepaired"
+	RouteRepairFailed  = "RouteRepairFailed"
+	OrphanRemoved      = "OrphanL3ConfigRemoved"
+	OrphanRemoveFailed = "OrphanL3ConfigRemoveFailed"
+)
+
+// EventHandler records events of daemon-side changes for the operator
+type EventHandler struct {
+	Clientset kubernetes.Interface
+	HostName  string
+}
+
+func NewEventHandler(clientset kubernetes.Interface, hostName string) *EventHandler {
+	return &EventHandler{
+		Clientset: clientset,
+		HostName:  hostName,
+	}
+}
+
+// RecordCIDREvent records an event on the cluster-scoped CIDR of the network
+func (h *EventHandler) RecordCIDREvent(cidrName string, eventType string, reason string, message string) error {
+	return h.recordEvent(v1.ObjectReference{
+		APIVersion: CIDR_API_VERSION,
+		Kind:       CIDR_KIND,
+		Name:       cidrName,
+	}, eventType, reason, message)
+}
+
+// RecordNodeEvent records an event on the node of the daemon
+func (h *EventHandler) RecordNodeEvent(eventType string, reason string, message string) error {
+	return h.recordEvent(v1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Node",
+		Name:       h.HostName,
+		UID:        types.UID(h.HostName),
+	}, eventType, reason, message)
+}
+
+func (h *EventHandler) recordEvent(involvedObject v1.ObjectReference, eventType string, reason string, message string) error {
+	if h.Clientset == nil {
+		return fmt.Errorf("no clientset")
+	}
+	now := metav1.NewTime(time.Now())
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s.", involvedObject.Name),
+			Namespace:    metav1.NamespaceDefault,
+		},
+		InvolvedObject: involvedObject,
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Source: v1.EventSource{
+			Component: EVENT_COMPONENT,
+			Host:      h.HostName,
+		},
+	}
+	_, err := h.Clientset.CoreV1().Events(metav1.NamespaceDefault).Create(context.TODO(), event, metav1.CreateOptions{})
+	return err
+}
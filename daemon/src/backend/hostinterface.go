@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
@@ -71,3 +72,25 @@ func (h *HostInterfaceHandler) GetHostInterfaces() ([]iface.InterfaceInfoType, e
 	}
 	return []iface.InterfaceInfoType{}, err
 }
+
+// UpdateHostInterfaces replaces interfaces in spec of HostInterface of the host
+func (h *HostInterfaceHandler) UpdateHostInterfaces(infos []iface.InterfaceInfoType) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		hifobj, err := h.DynamicHandler.Get(h.hostName, metav1.NamespaceAll, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		spec, ok := hifobj.Object["spec"].(map[string]interface{})
+		if !ok {
+			spec = map[string]interface{}{"hostName": h.hostName}
+			hifobj.Object["spec"] = spec
+		}
+		interfaces := []interface{}{}
+		for _, info := range infos {
+			interfaces = append(interfaces, h.DynamicHandler.Untidy(info))
+		}
+		spec["interfaces"] = interfaces
+		_, err = h.DynamicHandler.Update(hifobj.Object, metav1.NamespaceAll, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -26,8 +26,17 @@ type MultiNicNetworkSpec struct {
 }
 
 type AttachmentPolicy struct {
-	Strategy string `json:"strategy"`
-	Target   string `json:"target,omitempty"`
+	Strategy string            `json:"strategy"`
+	Target   string            `json:"target,omitempty"`
+	Mode     string            `json:"mode,omitempty"`
+	Filters  []string          `json:"filters,omitempty"`
+	Scores   []ScorePluginSpec `json:"scores,omitempty"`
+}
+
+// ScorePluginSpec is a score plugin with its weight
+type ScorePluginSpec struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight,omitempty"`
 }
 
 type MultiNicNetworkHandler struct {
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iface
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+var SysClassNet = "/sys/class/net"
+
+// setLinkAttributes sets speed, MTU, state, driver, NUMA node, SR-IOV, and RDMA device of the link to info,
+// attributes not available on the link are left empty
+func setLinkAttributes(info *InterfaceInfoType, devLink netlink.Link) {
+	devName := devLink.Attrs().Name
+	info.MTU = devLink.Attrs().MTU
+	info.OperState = devLink.Attrs().OperState.String()
+	info.Carrier = readSysInt(devName, "carrier") == 1
+	if speed := readSysInt(devName, "speed"); speed > 0 {
+		// unknown speed is -1 or not readable if link is down
+		info.Speed = speed
+	}
+	info.Driver, info.FirmwareVersion = getDriverInfo(devName)
+	if numaNode := readSysInt(devName, "device/numa_node"); numaNode >= 0 {
+		info.NumaNode = strconv.Itoa(numaNode)
+	}
+	if totalVFs := readSysInt(devName, "device/sriov_totalvfs"); totalVFs > 0 {
+		info.SriovTotalVFs = totalVFs
+		info.SriovNumVFs = readSysInt(devName, "device/sriov_numvfs")
+	}
+	if entries, err := os.ReadDir(filepath.Join(SysClassNet, devName, "device/infiniband")); err == nil && len(entries) > 0 {
+		info.RdmaDevice = entries[0].Name()
+	}
+}
+
+// getDriverInfo returns driver and firmware version by ethtool, driver from sysfs if ethtool is not supported
+func getDriverInfo(devName string) (string, string) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, 0)
+	if err == nil {
+		defer unix.Close(fd)
+		if drvInfo, err := unix.IoctlGetEthtoolDrvinfo(fd, devName); err == nil {
+			return unix.ByteSliceToString(drvInfo.Driver[:]), unix.ByteSliceToString(drvInfo.Fw_version[:])
+		}
+	}
+	if driverPath, err := filepath.EvalSymlinks(filepath.Join(SysClassNet, devName, "device/driver")); err == nil {
+		return filepath.Base(driverPath), ""
+	}
+	return "", ""
+}
+
+// readSysInt reads integer attribute of the device from sysfs, -1 if not readable
+func readSysInt(devName string, attr string) int {
+	content, err := os.ReadFile(filepath.Join(SysClassNet, devName, attr))
+	if err != nil {
+		return -1
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return -1
+	}
+	return value
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iface
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/vishvananda/netlink"
+)
+
+// SlaveInfo is a slave interface of bond or team master
+type SlaveInfo struct {
+	InterfaceName string `json:"interfaceName"`
+	PciAddress    string `json:"pciAddress,omitempty"`
+}
+
+func isBondMaster(link netlink.Link) bool {
+	return link.Type() == "bond" || link.Type() == "team"
+}
+
+// GetBondDevices returns bond and team masters of links as network devices
+// with vendor, product, and PCI address of their first PCI slave, and the set of slave names
+func GetBondDevices(links []netlink.Link, pciDevices []NetDeviceInfo) ([]NetDeviceInfo, map[string]bool) {
+	pciDeviceMap := make(map[string]NetDeviceInfo)
+	for _, dev := range pciDevices {
+		pciDeviceMap[dev.Name] = dev
+	}
+	masterMap := make(map[int]netlink.Link)
+	for _, link := range links {
+		if isBondMaster(link) {
+			masterMap[link.Attrs().Index] = link
+		}
+	}
+	slaveMap := make(map[int][]string)
+	slaveNames := make(map[string]bool)
+	for _, link := range links {
+		masterIndex := link.Attrs().MasterIndex
+		if _, found := masterMap[masterIndex]; found {
+			slaveMap[masterIndex] = append(slaveMap[masterIndex], link.Attrs().Name)
+			slaveNames[link.Attrs().Name] = true
+		}
+	}
+	bondDevices := []NetDeviceInfo{}
+	for index, master := range masterMap {
+		bondDevice := NetDeviceInfo{Name: master.Attrs().Name}
+		names := slaveMap[index]
+		sort.Strings(names)
+		for _, name := range names {
+			slaveDevice := pciDeviceMap[name]
+			if bondDevice.PciAddress == "" && slaveDevice.PciAddress != "" {
+				bondDevice.Vendor = slaveDevice.Vendor
+				bondDevice.Product = slaveDevice.Product
+				bondDevice.PciAddress = slaveDevice.PciAddress
+			}
+			bondDevice.Slaves = append(bondDevice.Slaves, SlaveInfo{InterfaceName: name, PciAddress: slaveDevice.PciAddress})
+		}
+		log.Printf("Detected %s interface: %s (slaves: %v)", master.Type(), bondDevice.Name, names)
+		bondDevices = append(bondDevices, bondDevice)
+	}
+	sort.Slice(bondDevices, func(i, j int) bool {
+		return bondDevices[i].Name < bondDevices[j].Name
+	})
+	return bondDevices, slaveNames
+}
+
+// setSlaveAttributes sets NUMA node and RDMA device of bond or team master from its first slave having them
+func setSlaveAttributes(info *InterfaceInfoType, slaves []SlaveInfo) {
+	info.Slaves = slaves
+	for _, slave := range slaves {
+		if info.NumaNode == "" {
+			if numaNode := readSysInt(slave.InterfaceName, "device/numa_node"); numaNode >= 0 {
+				info.NumaNode = strconv.Itoa(numaNode)
+			}
+		}
+		if info.RdmaDevice == "" {
+			if entries, err := os.ReadDir(filepath.Join(SysClassNet, slave.InterfaceName, "device/infiniband")); err == nil && len(entries) > 0 {
+				info.RdmaDevice = entries[0].Name()
+			}
+		}
+	}
+}
+
+// getBondMasterName returns name of bond or team master if the device is its slave
+func getBondMasterName(devName string) (string, bool) {
+	link, err := netlink.LinkByName(devName)
+	if err != nil || link.Attrs().MasterIndex == 0 {
+		return "", false
+	}
+	master, err := netlink.LinkByIndex(link.Attrs().MasterIndex)
+	if err != nil || !isBondMaster(master) {
+		return "", false
+	}
+	return master.Attrs().Name, true
+}
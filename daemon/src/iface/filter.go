@@ -0,0 +1,132 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iface
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// INTERFACE_FILTER_ENV is interface filter of Config daemon spec in JSON
+const INTERFACE_FILTER_ENV = "INTERFACE_FILTER"
+
+// InterfaceFilter selects interfaces of the host that can become masters
+type InterfaceFilter struct {
+	Include []InterfaceSelector `json:"include,omitempty"`
+	Exclude []InterfaceSelector `json:"exclude,omitempty"`
+}
+
+// InterfaceSelector matches interface if all of its set fields match, a field matches any of its values
+type InterfaceSelector struct {
+	Names        []string `json:"names,omitempty"`
+	PciAddresses []string `json:"pciAddresses,omitempty"`
+	Vendors      []string `json:"vendors,omitempty"`
+	Products     []string `json:"products,omitempty"`
+	Drivers      []string `json:"drivers,omitempty"`
+	Subnets      []string `json:"subnets,omitempty"`
+}
+
+var interfaceFilter *InterfaceFilter
+
+// LoadInterfaceFilter sets interface filter from INTERFACE_FILTER, no filter if not set or invalid
+func LoadInterfaceFilter() {
+	filterStr, found := os.LookupEnv(INTERFACE_FILTER_ENV)
+	if !found || filterStr == "" {
+		interfaceFilter = nil
+		return
+	}
+	filter := &InterfaceFilter{}
+	if err := json.Unmarshal([]byte(filterStr), filter); err != nil {
+		log.Printf("invalid %s=%s, no filter: %v", INTERFACE_FILTER_ENV, filterStr, err)
+		interfaceFilter = nil
+		return
+	}
+	log.Printf("interface filter: %s", filterStr)
+	interfaceFilter = filter
+}
+
+// SetInterfaceFilter sets interface filter, nil to disable
+func SetInterfaceFilter(filter *InterfaceFilter) {
+	interfaceFilter = filter
+}
+
+// Match checks if interface is included and not excluded
+func (f *InterfaceFilter) Match(info InterfaceInfoType) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Include) > 0 && !matchAnySelector(f.Include, info) {
+		return false
+	}
+	return !matchAnySelector(f.Exclude, info)
+}
+
+func matchAnySelector(selectors []InterfaceSelector, info InterfaceInfoType) bool {
+	for _, selector := range selectors {
+		if selector.Match(info) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match checks if interface matches all set fields of the selector
+func (s InterfaceSelector) Match(info InterfaceInfoType) bool {
+	if len(s.Names) > 0 && !matchAny(s.Names, func(pattern string) bool {
+		matched, err := filepath.Match(pattern, info.InterfaceName)
+		return err == nil && matched
+	}) {
+		return false
+	}
+	if len(s.PciAddresses) > 0 && !matchAny(s.PciAddresses, func(pciAddress string) bool {
+		return strings.EqualFold(pciAddress, info.PciAddress)
+	}) {
+		return false
+	}
+	if len(s.Vendors) > 0 && !matchAny(s.Vendors, func(vendor string) bool {
+		return strings.EqualFold(vendor, info.Vendor)
+	}) {
+		return false
+	}
+	if len(s.Products) > 0 && !matchAny(s.Products, func(product string) bool {
+		return strings.EqualFold(product, info.Product)
+	}) {
+		return false
+	}
+	if len(s.Drivers) > 0 && !matchAny(s.Drivers, func(driver string) bool {
+		return driver == info.Driver
+	}) {
+		return false
+	}
+	if len(s.Subnets) > 0 && !matchAny(s.Subnets, func(subnet string) bool {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return false
+		}
+		for _, addr := range info.GetAddresses() {
+			if ip := net.ParseIP(addr.HostIP); ip != nil && ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}) {
+		return false
+	}
+	return true
+}
+
+func matchAny(values []string, match func(value string) bool) bool {
+	for _, value := range values {
+		if match(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -22,6 +22,33 @@ type InterfaceInfoType struct {
 	Vendor        string `json:"vendor"`
 	Product       string `json:"product"`
 	PciAddress    string `json:"pciAddress"`
+	// Speed is link speed in Mbps, zero if unknown
+	Speed           int    `json:"speed,omitempty"`
+	MTU             int    `json:"mtu,omitempty"`
+	OperState       string `json:"operState,omitempty"`
+	Carrier         bool   `json:"carrier,omitempty"`
+	Driver          string `json:"driver,omitempty"`
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// NumaNode is NUMA node of PCI device, empty if not applicable
+	NumaNode      string `json:"numaNode,omitempty"`
+	SriovTotalVFs int    `json:"sriovTotalVFs,omitempty"`
+	SriovNumVFs   int    `json:"sriovNumVFs,omitempty"`
+	RdmaDevice    string `json:"rdmaDevice,omitempty"`
+	// SecondaryAddresses are IPv4 addresses other than the first one
+	SecondaryAddresses []InterfaceAddress `json:"secondaryAddresses,omitempty"`
+	// Slaves are slave interfaces of bond or team master
+	Slaves []SlaveInfo `json:"slaves,omitempty"`
+}
+
+// InterfaceAddress is an IPv4 address of interface with its network address
+type InterfaceAddress struct {
+	NetAddress string `json:"netAddress"`
+	HostIP     string `json:"hostIP"`
+}
+
+// GetAddresses returns the first and secondary addresses of interface
+func (i InterfaceInfoType) GetAddresses() []InterfaceAddress {
+	return append([]InterfaceAddress{{NetAddress: i.NetAddress, HostIP: i.HostIP}}, i.SecondaryAddresses...)
 }
 
 const (
@@ -92,10 +119,13 @@ func GetInterfaceNameMap() map[string]map[string]string {
 	}
 	interfaceMap := GetInterfaceInfoCache()
 	for devName, info := range interfaceMap {
-		if _, found := ifaceNameMap[info.NetAddress]; !found {
-			ifaceNameMap[info.NetAddress] = make(map[string]string)
+		// interface with multiple addresses can be found by any of its network addresses
+		for _, addr := range info.GetAddresses() {
+			if _, found := ifaceNameMap[addr.NetAddress]; !found {
+				ifaceNameMap[addr.NetAddress] = make(map[string]string)
+			}
+			ifaceNameMap[addr.NetAddress][info.PciAddress] = devName
 		}
-		ifaceNameMap[info.NetAddress][info.PciAddress] = devName
 	}
 	return ifaceNameMap
 }
@@ -167,6 +197,23 @@ func GetInterfaces() []InterfaceInfoType {
 				Product:       netDevice.Product,
 				PciAddress:    netDevice.PciAddress,
 			}
+			for _, secondaryAddr := range addrs[1:] {
+				if secondaryAddr.IPNet == nil || secondaryAddr.IP.To4() == nil {
+					continue
+				}
+				iface.SecondaryAddresses = append(iface.SecondaryAddresses, InterfaceAddress{
+					NetAddress: getNetAddress(secondaryAddr.IPNet),
+					HostIP:     secondaryAddr.IP.To4().String(),
+				})
+			}
+			setLinkAttributes(&iface, devLink)
+			if len(netDevice.Slaves) > 0 {
+				setSlaveAttributes(&iface, netDevice.Slaves)
+			}
+			if !interfaceFilter.Match(iface) {
+				log.Printf("filter out %s", devName)
+				continue
+			}
 			interfaces = append(interfaces, iface)
 			interfaceInfoCache.SetCache(devName, iface)
 		}
@@ -53,6 +53,8 @@ type NetDeviceInfo struct {
 	Vendor     string
 	Product    string
 	PciAddress string
+	// Slaves are slave interfaces if device is bond or team master
+	Slaves []SlaveInfo
 }
 
 func SetDeviceMapCache(pciAddresss, name string) {
@@ -93,7 +95,8 @@ func convertDeviceIDs(deviceIDs interface{}) []string {
 	return deviceIDsArray
 }
 
-// GetPodResourceMap returns a map from resource name to device ID
+// GetPodResourceMap returns a map from resource name to device ID from kubelet PodResources API,
+// or from kubelet checkpoint if the API is not available
 func GetPodResourceMap(pod *v1.Pod) (map[string][]string, error) {
 	resourceMap := make(map[string][]string)
 	podID := string(pod.UID)
@@ -101,6 +104,11 @@ func GetPodResourceMap(pod *v1.Pod) (map[string][]string, error) {
 	if podID == "" {
 		return resourceMap, fmt.Errorf("GetPodResourceMap: invalid Pod cannot be empty")
 	}
+	if kubeletResourceMap, err := getResourceMapFromKubelet(pod.GetNamespace(), pod.GetName()); err == nil {
+		return kubeletResourceMap, nil
+	} else {
+		log.Printf("cannot get pod resources from kubelet, read checkpoint: %v", err)
+	}
 	cpd, err := getCheckpointData()
 	if err != nil {
 		return resourceMap, err
@@ -132,6 +140,10 @@ func GetDeviceName(deviceID string) string {
 			log.Printf("cannot get physical device %s: %v\n", deviceID, err)
 			return ""
 		} else {
+			if bondName, found := getBondMasterName(masterName); found {
+				// slave is not selectable, use its master
+				masterName = bondName
+			}
 			log.Printf("set deviceMapCache %s=%s\n", deviceID, masterName)
 			deviceMapCache.SetCache(deviceID, masterName)
 			return masterName
@@ -213,6 +225,18 @@ func GetTargetNetworks() []NetDeviceInfo {
 		return netDevices
 	}
 
+	// Replace slaves with their bond or team masters
+	bondDevices, slaveNames := GetBondDevices(links, netDevices)
+	masterDevices := []NetDeviceInfo{}
+	for _, dev := range netDevices {
+		if slaveNames[dev.Name] {
+			log.Printf("Skipping slave interface %s", dev.Name)
+			continue
+		}
+		masterDevices = append(masterDevices, dev)
+	}
+	netDevices = append(masterDevices, bondDevices...)
+
 	for _, link := range links {
 		devName := link.Attrs().Name
 
@@ -243,7 +267,7 @@ func GetTargetNetworks() []NetDeviceInfo {
 				continue
 			}
 			parentName := parentLink.Attrs().Name
-			if parentName != "tenant-bond" {
+			if parentName != "tenant-bond" && !isBondMaster(parentLink) {
 				log.Printf("Skipping VLAN interface %s (parent: %s)", devName, parentName)
 				continue
 			}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iface
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+const podResourcesTimeout = 10 * time.Second
+
+var PodResourcesSocket string = "/var/lib/kubelet/pod-resources/kubelet.sock"
+
+// ContainerResources are devices with their NUMA nodes and CPUs allocated to container
+type ContainerResources struct {
+	Name string
+	// Devices maps resource name to device IDs
+	Devices map[string][]string
+	// DeviceNumaNodes maps device ID to NUMA nodes of its topology hint
+	DeviceNumaNodes map[string][]int64
+	CpuIds          []int64
+}
+
+// getContainerResources converts container resources of kubelet PodResources API
+func getContainerResources(containers []*podresourcesapi.ContainerResources) []ContainerResources {
+	containerResources := []ContainerResources{}
+	for _, container := range containers {
+		resources := ContainerResources{
+			Name:            container.GetName(),
+			Devices:         make(map[string][]string),
+			DeviceNumaNodes: make(map[string][]int64),
+			CpuIds:          container.GetCpuIds(),
+		}
+		for _, devices := range container.GetDevices() {
+			resourceName := devices.GetResourceName()
+			resources.Devices[resourceName] = append(resources.Devices[resourceName], devices.GetDeviceIds()...)
+			numaNodes := []int64{}
+			for _, node := range devices.GetTopology().GetNodes() {
+				numaNodes = append(numaNodes, node.GetID())
+			}
+			if len(numaNodes) > 0 {
+				for _, deviceID := range devices.GetDeviceIds() {
+					resources.DeviceNumaNodes[deviceID] = numaNodes
+				}
+			}
+		}
+		containerResources = append(containerResources, resources)
+	}
+	return containerResources
+}
+
+// GetPodContainerResources returns resources of each container of pod from kubelet PodResources API
+func GetPodContainerResources(namespace, name string) ([]ContainerResources, error) {
+	if _, err := os.Stat(PodResourcesSocket); err != nil {
+		return nil, fmt.Errorf("cannot access kubelet socket %s: %v", PodResourcesSocket, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), podResourcesTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "unix://"+PodResourcesSocket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to kubelet socket %s: %v", PodResourcesSocket, err)
+	}
+	defer conn.Close()
+	client := podresourcesapi.NewPodResourcesListerClient(conn)
+	resp, err := client.List(ctx, &podresourcesapi.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list pod resources: %v", err)
+	}
+	for _, podResources := range resp.GetPodResources() {
+		if podResources.GetNamespace() == namespace && podResources.GetName() == name {
+			return getContainerResources(podResources.GetContainers()), nil
+		}
+	}
+	return nil, fmt.Errorf("pod %s/%s not found in pod resources", namespace, name)
+}
+
+// getResourceMapFromKubelet returns a map from resource name to device IDs of all containers of pod
+func getResourceMapFromKubelet(namespace, name string) (map[string][]string, error) {
+	resourceMap := make(map[string][]string)
+	containerResources, err := GetPodContainerResources(namespace, name)
+	if err != nil {
+		return resourceMap, err
+	}
+	for _, container := range containerResources {
+		log.Printf("container %s of %s/%s: devices=%v, numa=%v, cpus=%v", container.Name, namespace, name,
+			container.Devices, container.DeviceNumaNodes, container.CpuIds)
+		for resourceName, deviceIDs := range container.Devices {
+			resourceMap[resourceName] = append(resourceMap[resourceName], deviceIDs...)
+		}
+	}
+	return resourceMap, nil
+}
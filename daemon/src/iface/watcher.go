@@ -0,0 +1,94 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iface
+
+import (
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// INTERFACE_DEBOUNCE waits for consecutive link and address updates of the same change
+	INTERFACE_DEBOUNCE = 1 * time.Second
+	// INTERFACE_RETRY_INTERVAL is interval to retry reporting interfaces after failure
+	INTERFACE_RETRY_INTERVAL = 10 * time.Second
+)
+
+// WatchInterfaces reports interfaces by onChange at start and whenever
+// added, removed, renamed, or readdressed interfaces are found from link and address updates
+func WatchInterfaces(onChange func(interfaces []InterfaceInfoType) error, quit <-chan struct{}) {
+	linkCh := make(chan netlink.LinkUpdate)
+	addrCh := make(chan netlink.AddrUpdate)
+	if err := netlink.LinkSubscribe(linkCh, quit); err != nil {
+		log.Printf("failed to subscribe link updates: %v", err)
+	}
+	if err := netlink.AddrSubscribe(addrCh, quit); err != nil {
+		log.Printf("failed to subscribe address updates: %v", err)
+	}
+	var reported []InterfaceInfoType
+	reportedOnce := false
+	debounce := time.NewTimer(INTERFACE_DEBOUNCE)
+	defer debounce.Stop()
+	log.Printf("start watching interfaces")
+	for {
+		select {
+		case <-quit:
+			return
+		case _, ok := <-linkCh:
+			if !ok {
+				linkCh = nil
+				continue
+			}
+			debounce.Reset(INTERFACE_DEBOUNCE)
+		case _, ok := <-addrCh:
+			if !ok {
+				addrCh = nil
+				continue
+			}
+			debounce.Reset(INTERFACE_DEBOUNCE)
+		case <-debounce.C:
+			interfaces := GetInterfaces()
+			if reportedOnce && IsSameInterfaces(reported, interfaces) {
+				continue
+			}
+			if err := onChange(interfaces); err != nil {
+				log.Printf("failed to report %d interface(s), retry in %v: %v", len(interfaces), INTERFACE_RETRY_INTERVAL, err)
+				debounce.Reset(INTERFACE_RETRY_INTERVAL)
+				continue
+			}
+			log.Printf("report %d interface(s): %v", len(interfaces), interfaces)
+			reported = interfaces
+			reportedOnce = true
+		}
+	}
+}
+
+// IsSameInterfaces checks if both have the same interfaces regardless of order
+func IsSameInterfaces(interfaces []InterfaceInfoType, cmpInterfaces []InterfaceInfoType) bool {
+	if len(interfaces) != len(cmpInterfaces) {
+		return false
+	}
+	sorted := sortInterfaces(interfaces)
+	cmpSorted := sortInterfaces(cmpInterfaces)
+	for index := range sorted {
+		if !reflect.DeepEqual(sorted[index], cmpSorted[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortInterfaces(interfaces []InterfaceInfoType) []InterfaceInfoType {
+	sorted := append([]InterfaceInfoType{}, interfaces...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].InterfaceName < sorted[j].InterfaceName
+	})
+	return sorted
+}
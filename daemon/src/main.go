@@ -20,10 +20,12 @@ import (
 	"github.com/gorilla/mux"
 
 	da "github.com/foundation-model-stack/multi-nic-cni/daemon/allocator"
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/apierror"
 	"github.com/foundation-model-stack/multi-nic-cni/daemon/backend"
 	di "github.com/foundation-model-stack/multi-nic-cni/daemon/iface"
 	dr "github.com/foundation-model-stack/multi-nic-cni/daemon/router"
 	ds "github.com/foundation-model-stack/multi-nic-cni/daemon/selector"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -170,6 +172,16 @@ func DeleteRoute(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// writeResponse writes daemon API error if any, otherwise encodes response
+func writeResponse(w http.ResponseWriter, response interface{}, err error) {
+	if apiErr, ok := err.(*apierror.ErrorResponse); ok {
+		log.Println(fmt.Sprintf("response error %d: %v", apiErr.StatusCode(), apiErr))
+		apiErr.Write(w)
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
 func SelectNic(w http.ResponseWriter, r *http.Request) {
 	startSelect := time.Now()
 	reqBody, _ := io.ReadAll(r.Body)
@@ -183,14 +195,15 @@ func SelectNic(w http.ResponseWriter, r *http.Request) {
 	var resp ds.NICSelectResponse
 	if err == nil {
 		log.Println(fmt.Sprintf("request: %v", req))
-		resp = ds.Select(req)
+		resp, err = ds.Select(req)
 		elapsed := time.Since(startSelect)
 		log.Println(fmt.Sprintf("%s SelectNic elapsed: %d us", req.HostName, int64(elapsed/time.Microsecond)))
 		log.Println(fmt.Sprintf("return: %v", resp))
 	} else {
 		log.Println(fmt.Sprintf("select fail: %v", err))
+		err = apierror.New(apierror.InvalidRequest, "", hostName, fmt.Sprintf("failed to parse request: %v", err))
 	}
-	json.NewEncoder(w).Encode(resp)
+	writeResponse(w, resp, err)
 }
 
 func Allocate(w http.ResponseWriter, r *http.Request) {
@@ -206,14 +219,15 @@ func Allocate(w http.ResponseWriter, r *http.Request) {
 	var ipResponses []da.IPResponse
 	if err == nil {
 		log.Println(fmt.Sprintf("request: %v", req))
-		ipResponses = da.AllocateIP(req)
+		ipResponses, err = da.AllocateIP(req)
 		elapsed := time.Since(startAllocate)
 		log.Println(fmt.Sprintf("%s WaitAndAllocate elapsed: %d us", req.HostName, int64(elapsed/time.Microsecond)))
 		log.Println(fmt.Sprintf("return: %v", ipResponses))
 	} else {
 		log.Println(fmt.Sprintf("allocate fail: %v", err))
+		err = apierror.New(apierror.InvalidRequest, "", hostName, fmt.Sprintf("failed to parse request: %v", err))
 	}
-	json.NewEncoder(w).Encode(ipResponses)
+	writeResponse(w, ipResponses, err)
 }
 
 func Deallocate(w http.ResponseWriter, r *http.Request) {
@@ -227,9 +241,12 @@ func Deallocate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err == nil {
-		ipResponses = da.DeallocateIP(req)
+		ipResponses, err = da.DeallocateIP(req)
+	} else {
+		log.Println(fmt.Sprintf("deallocate fail: %v", err))
+		err = apierror.New(apierror.InvalidRequest, "", hostName, fmt.Sprintf("failed to parse request: %v", err))
 	}
-	json.NewEncoder(w).Encode(ipResponses)
+	writeResponse(w, ipResponses, err)
 }
 
 func InitClient() *rest.Config {
@@ -255,10 +272,82 @@ func initHandlers(config *rest.Config) {
 	ds.MultinicnetHandler = backend.NewMultiNicNetworkHandler(config)
 	ds.NetAttachDefHandler = backend.NewNetAttachDefHandler(config)
 	ds.DeviceClassHandler = backend.NewDeviceClassHandler(config)
+	ds.IPPoolHandler = da.IppoolHandler
 	da.K8sClientset, _ = kubernetes.NewForConfig(config)
 	ds.K8sClientset, _ = kubernetes.NewForConfig(config)
 }
 
+func newEventHandler(config *rest.Config) *backend.EventHandler {
+	eventHandler := backend.NewEventHandler(nil, hostName)
+	if clientset, err := kubernetes.NewForConfig(config); err == nil {
+		eventHandler.Clientset = clientset
+	} else {
+		log.Printf("cannot report L3 events: %v", err)
+	}
+	return eventHandler
+}
+
+// cleanOrphanL3Configs removes tables and rules of networks deleted while the daemon was down
+// and reports each removal as event of the node
+func cleanOrphanL3Configs(config *rest.Config, eventHandler *backend.EventHandler) {
+	specs, err := backend.NewCIDRHandler(config).ListNetworks()
+	if err != nil {
+		log.Printf("skip orphan L3 config cleanup, cannot list CIDRs: %v", err)
+		return
+	}
+	// table name to subnet of networks and their rails
+	networks := make(map[string]string)
+	for name, spec := range specs {
+		networks[name] = spec.Config.Subnet
+		for _, entry := range spec.CIDRs {
+			networks[dr.GetRailTableName(name, entry.InterfaceIndex)] = entry.VlanCIDR
+		}
+	}
+	orphans, err := dr.CleanOrphanL3Configs(networks)
+	if err != nil {
+		log.Printf("failed to clean orphan L3 configs: %v", err)
+	}
+	for _, orphan := range orphans {
+		eventType, reason := v1.EventTypeNormal, backend.OrphanRemoved
+		if !orphan.Success {
+			eventType, reason = v1.EventTypeWarning, backend.OrphanRemoveFailed
+		}
+		message := fmt.Sprintf("table %d (name=%s, rule from %v, %d route(s)) of no existing CIDR removed=%v %s", orphan.TableID, orphan.Name, orphan.Subnets, orphan.Routes, orphan.Success, orphan.Error)
+		if err := eventHandler.RecordNodeEvent(eventType, reason, message); err != nil {
+			log.Printf("failed to report orphan table %d: %v", orphan.TableID, err)
+		}
+	}
+}
+
+// initSelfHealing starts restoring L3 configs and reports each repair as event of CIDR
+func initSelfHealing(eventHandler *backend.EventHandler) {
+	dr.RepairHandler = func(report dr.RepairReport) {
+		eventType, reason := v1.EventTypeNormal, backend.RouteRepaired
+		if !report.Success {
+			eventType, reason = v1.EventTypeWarning, backend.RouteRepairFailed
+		}
+		changes := []string{}
+		for _, outcome := range report.RouteOutcomes {
+			change := fmt.Sprintf("%s %s via %s dev %s", outcome.Action, outcome.Subnet, outcome.NextHop, outcome.InterfaceName)
+			if !outcome.Success {
+				change += fmt.Sprintf(" (%s)", outcome.Error)
+			}
+			changes = append(changes, change)
+		}
+		message := fmt.Sprintf("host %s: rule restored=%v; routes: [%s]", hostName, report.RuleRestored, strings.Join(changes, "; "))
+		if err := eventHandler.RecordCIDREvent(report.Name, eventType, reason, message); err != nil {
+			log.Printf("failed to report L3 repair of %s: %v", report.Name, err)
+		}
+	}
+	go dr.StartSelfHealing(dr.GetHealInterval(), make(chan struct{}))
+}
+
+// initInterfaceWatcher writes interfaces of the host to its HostInterface on link and address updates
+func initInterfaceWatcher(config *rest.Config) {
+	hostInterfaceHandler := backend.NewHostInterfaceHandler(config, hostName)
+	go di.WatchInterfaces(hostInterfaceHandler.UpdateHostInterfaces, make(chan struct{}))
+}
+
 func initHostName() {
 	var err error
 	var found bool
@@ -283,8 +372,14 @@ func main() {
 		}
 	}
 	dr.SetRTTablePath()
+	di.LoadInterfaceFilter()
 	ds.InitCache(cfg, hostName)
+	ds.InitGPUResolver()
 	da.CleanHangingAllocation(hostName)
+	eventHandler := newEventHandler(cfg)
+	cleanOrphanL3Configs(cfg, eventHandler)
+	initSelfHealing(eventHandler)
+	initInterfaceWatcher(cfg)
 	router := handleRequests()
 	daemonAddress := fmt.Sprintf("0.0.0.0:%d", DAEMON_PORT)
 	log.Printf("Serving at %s", daemonAddress)
@@ -7,17 +7,25 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 
 	da "github.com/foundation-model-stack/multi-nic-cni/daemon/allocator"
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/apierror"
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/backend"
 	di "github.com/foundation-model-stack/multi-nic-cni/daemon/iface"
 	dr "github.com/foundation-model-stack/multi-nic-cni/daemon/router"
 	ds "github.com/foundation-model-stack/multi-nic-cni/daemon/selector"
 	"github.com/vishvananda/netlink"
+	"google.golang.org/grpc"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
 
 	"log"
 
@@ -153,6 +161,51 @@ var _ = Describe("Test Get Interfaces", func() {
 		json.Unmarshal(body, &response)
 		log.Printf("TestUpdateInterface: %v", response)
 	})
+
+	It("filters interfaces", func() {
+		info := di.InterfaceInfoType{InterfaceName: "eth1", HostIP: "10.0.1.1", Vendor: "15b3", Driver: "mlx5_core"}
+		var filter *di.InterfaceFilter
+		Expect(filter.Match(info)).To(BeTrue())
+		filter = &di.InterfaceFilter{
+			Include: []di.InterfaceSelector{{Names: []string{"eth*"}, Vendors: []string{"15B3"}}},
+			Exclude: []di.InterfaceSelector{{Subnets: []string{"10.0.2.0/24"}}},
+		}
+		Expect(filter.Match(info)).To(BeTrue())
+		info.HostIP = "10.0.2.1"
+		Expect(filter.Match(info)).To(BeFalse())
+		info.HostIP = "10.0.1.1"
+		info.InterfaceName = "ib0"
+		Expect(filter.Match(info)).To(BeFalse())
+		filter.Include = nil
+		filter.Exclude = []di.InterfaceSelector{{Drivers: []string{"mlx5_core"}}}
+		Expect(filter.Match(info)).To(BeFalse())
+	})
+
+	It("replaces bond slaves with master", func() {
+		links := []netlink.Link{
+			&netlink.Bond{LinkAttrs: netlink.LinkAttrs{Name: "bond0", Index: 10}},
+			&netlink.GenericLink{LinkAttrs: netlink.LinkAttrs{Name: "team0", Index: 11}, LinkType: "team"},
+			&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth2", Index: 3, MasterIndex: 10}},
+			&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth1", Index: 2, MasterIndex: 10}},
+			&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth3", Index: 4}},
+		}
+		pciDevices := []di.NetDeviceInfo{
+			{Name: "eth1", Vendor: "15b3", Product: "101b", PciAddress: "0000:01:00.0"},
+			{Name: "eth2", Vendor: "15b3", Product: "101b", PciAddress: "0000:02:00.0"},
+			{Name: "eth3", Vendor: "15b3", Product: "101b", PciAddress: "0000:03:00.0"},
+		}
+		bondDevices, slaveNames := di.GetBondDevices(links, pciDevices)
+		Expect(slaveNames).To(Equal(map[string]bool{"eth1": true, "eth2": true}))
+		Expect(bondDevices).To(HaveLen(2))
+		Expect(bondDevices[0].Name).To(Equal("bond0"))
+		Expect(bondDevices[0].PciAddress).To(Equal("0000:01:00.0"))
+		Expect(bondDevices[0].Slaves).To(Equal([]di.SlaveInfo{
+			{InterfaceName: "eth1", PciAddress: "0000:01:00.0"},
+			{InterfaceName: "eth2", PciAddress: "0000:02:00.0"},
+		}))
+		Expect(bondDevices[1].Name).To(Equal("team0"))
+		Expect(bondDevices[1].PciAddress).To(BeEmpty())
+	})
 })
 
 var _ = Describe("Test Allocation", func() {
@@ -243,6 +296,29 @@ var _ = Describe("Test Allocation", func() {
 		MakeIPRequest(request2, DEALLOCATE_PATH, deallocateHandler, false)
 	})
 
+	It("allocate without ippool", func() {
+		request := da.IPRequest{
+			PodName:          POD_NAME,
+			PodNamespace:     POD_NAMESPACE,
+			HostName:         HOST_NAME,
+			NetAttachDefName: "no-ippool-net",
+			InterfaceNames:   MASTER_INTERFACES,
+		}
+		encoded, err := json.Marshal(request)
+		Expect(err).NotTo(HaveOccurred())
+		req, err := http.NewRequest("POST", ALLOCATE_PATH, bytes.NewBuffer(encoded))
+		Expect(err).NotTo(HaveOccurred())
+		res := httptest.NewRecorder()
+		http.HandlerFunc(Allocate).ServeHTTP(res, req)
+		Expect(res.Code).To(Equal(http.StatusNotFound))
+		var errResponse apierror.ErrorResponse
+		err = json.Unmarshal(res.Body.Bytes(), &errResponse)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errResponse.Reason).To(Equal(apierror.IPPoolNotFound))
+		Expect(errResponse.Network).To(Equal("no-ippool-net"))
+		Expect(errResponse.Missing).To(Equal(MASTER_INTERFACES))
+	})
+
 })
 
 var _ = Describe("Test NIC Select", func() {
@@ -319,6 +395,27 @@ var _ = Describe("Test NIC Select", func() {
 		_, ok := resourceMap[ds.GPUResourceName]
 		Expect(ok).To(Equal(true))
 	})
+	It("Get resource map from kubelet PodResources API", func() {
+		defaultSocket := di.PodResourcesSocket
+		di.PodResourcesSocket = filepath.Join(GinkgoT().TempDir(), "kubelet.sock")
+		defer func() { di.PodResourcesSocket = defaultSocket }()
+		listener, err := net.Listen("unix", di.PodResourcesSocket)
+		Expect(err).NotTo(HaveOccurred())
+		server := grpc.NewServer()
+		podresourcesapi.RegisterPodResourcesListerServer(server, fakePodResourcesLister{})
+		go server.Serve(listener)
+		defer server.Stop()
+
+		containerResources, err := di.GetPodContainerResources(targetPod.Namespace, targetPod.Name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(containerResources).To(HaveLen(1))
+		Expect(containerResources[0].Devices[ds.GPUResourceName]).To(Equal([]string{"GPU-0"}))
+		Expect(containerResources[0].DeviceNumaNodes["GPU-0"]).To(Equal([]int64{1}))
+		Expect(containerResources[0].CpuIds).To(Equal([]int64{2, 3}))
+		resourceMap, err := di.GetPodResourceMap(targetPod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resourceMap).To(Equal(map[string][]string{ds.GPUResourceName: {"GPU-0"}}))
+	})
 	It("select nic by NumaAwareSelector (topology)", func() {
 		setTestLatestInterfaces()
 		ds.TopologyFilePath = EXAMPLE_TOPOLOGY
@@ -344,6 +441,83 @@ var _ = Describe("Test NIC Select", func() {
 		// must select nic in numa 1
 		Expect(response.Masters[0]).To(Equal(MASTER_INTERFACES[1]))
 	})
+	It("pair GPU with NIC behind the same PCIe switch", func() {
+		setTestLatestInterfaces()
+		gpuBusMap := map[string]string{
+			"GPU-0": "00000000:0C:05.0",
+			"GPU-1": "0000:08:01.0",
+		}
+		selector := ds.InitNumaAwareSelector(EXAMPLE_TOPOLOGY, gpuBusMap)
+		interfaceNameMap := map[string]string{
+			MASTER_NETADDRESSES[0]: MASTER_INTERFACES[0],
+			MASTER_NETADDRESSES[1]: MASTER_INTERFACES[1],
+		}
+		resourceMap := map[string][]string{ds.GPUResourceName: {"GPU-0", "GPU-1"}}
+		sorted, ok := selector.SortByPCIeSwitch(MASTER_NETADDRESSES, interfaceNameMap, resourceMap)
+		Expect(ok).To(BeTrue())
+		Expect(sorted).To(Equal([]string{MASTER_NETADDRESSES[1], MASTER_NETADDRESSES[0]}))
+		resourceMap = map[string][]string{ds.GPUResourceName: {"GPU-unknown"}}
+		_, ok = selector.SortByPCIeSwitch(MASTER_NETADDRESSES, interfaceNameMap, resourceMap)
+		Expect(ok).To(BeFalse())
+	})
+	It("resolve GPU bus IDs from sysfs", func() {
+		pciDeviceDir := GinkgoT().TempDir()
+		devices := map[string]string{
+			"0000:0c:05.0": "0x030200",
+			"0000:1a:00.0": "0x120000",
+			"0000:0c:00.0": "0x020000",
+		}
+		for busId, class := range devices {
+			Expect(os.MkdirAll(filepath.Join(pciDeviceDir, busId), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(pciDeviceDir, busId, "class"), []byte(class+"\n"), 0644)).To(Succeed())
+		}
+		Expect(os.MkdirAll(filepath.Join(pciDeviceDir, "0000:1a:00.0", "accel", "accel0"), 0755)).To(Succeed())
+		defaultPciDeviceDir := ds.PciDeviceDir
+		ds.PciDeviceDir = pciDeviceDir
+		defer func() { ds.PciDeviceDir = defaultPciDeviceDir }()
+		Expect(ds.SysfsResolver{}.GetBusIDMap()).To(Equal(map[string]string{
+			"0000:0c:05.0": "0000:0c:05.0",
+			"0000:1a:00.0": "0000:1a:00.0",
+			"accel0":       "0000:1a:00.0",
+		}))
+	})
+	It("sort nic by attached count", func() {
+		interfaceNameMap := map[string]string{
+			"10.0.1.0/24": "eth1",
+			"10.0.2.0/24": "eth2",
+			"10.0.3.0/24": "eth3",
+		}
+		attachedCount := map[string]int{"eth1": 2, "eth2": 1}
+		sorted := ds.SortByAttachedCount([]string{"10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"}, interfaceNameMap, attachedCount)
+		Expect(sorted).To(Equal([]string{"10.0.3.0/24", "10.0.2.0/24", "10.0.1.0/24"}))
+		attachedCount = map[string]int{"eth1": 1, "eth2": 1, "eth3": 1}
+		sorted = ds.SortByAttachedCount([]string{"10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"}, interfaceNameMap, attachedCount)
+		Expect(sorted).To(Equal([]string{"10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"}))
+	})
+	It("select nic by filter and score plugins", func() {
+		setTestLatestInterfaces()
+		policy := backend.AttachmentPolicy{
+			Filters: []string{"masters", "health"},
+			Scores:  []backend.ScorePluginSpec{{Name: "cost", Weight: 2}},
+		}
+		interfaceNameMap := map[string]string{
+			MASTER_NETADDRESSES[0]: MASTER_INTERFACES[0],
+			MASTER_NETADDRESSES[1]: MASTER_INTERFACES[1],
+		}
+		request := ds.NICSelectRequest{
+			NicSet: ds.NicArgs{
+				InterfaceNames: []string{MASTER_INTERFACES[1]},
+			},
+		}
+		selected, explanation := ds.PipelineSelect(policy, request, interfaceNameMap, map[string][]string{})
+		Expect(selected).To(Equal([]string{MASTER_NETADDRESSES[1]}))
+		Expect(explanation.Filtered).To(HaveLen(1))
+		Expect(explanation.Filtered[0].Master).To(Equal(MASTER_INTERFACES[0]))
+		Expect(explanation.Filtered[0].Plugin).To(Equal("masters"))
+		Expect(explanation.Scores).To(HaveLen(1))
+		Expect(explanation.Scores[0].Total).To(Equal(2 * ds.MaxPluginScore))
+		Expect(explanation.Scores[0].Selected).To(BeTrue())
+	})
 })
 
 func setTestLatestInterfaces() {
@@ -401,3 +575,27 @@ func getValidIface() string {
 	Expect(notFound).To(BeFalse())
 	return ""
 }
+
+type fakePodResourcesLister struct{}
+
+func (fakePodResourcesLister) List(context.Context, *podresourcesapi.ListPodResourcesRequest) (*podresourcesapi.ListPodResourcesResponse, error) {
+	return &podresourcesapi.ListPodResourcesResponse{
+		PodResources: []*podresourcesapi.PodResources{{
+			Name:      targetPod.Name,
+			Namespace: targetPod.Namespace,
+			Containers: []*podresourcesapi.ContainerResources{{
+				Name:   "main",
+				CpuIds: []int64{2, 3},
+				Devices: []*podresourcesapi.ContainerDevices{{
+					ResourceName: ds.GPUResourceName,
+					DeviceIds:    []string{"GPU-0"},
+					Topology:     &podresourcesapi.TopologyInfo{Nodes: []*podresourcesapi.NUMANode{{ID: 1}}},
+				}},
+			}},
+		}},
+	}, nil
+}
+
+func (fakePodResourcesLister) GetAllocatableResources(context.Context, *podresourcesapi.AllocatableResourcesRequest) (*podresourcesapi.AllocatableResourcesResponse, error) {
+	return &podresourcesapi.AllocatableResourcesResponse{}, nil
+}
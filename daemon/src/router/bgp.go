@@ -0,0 +1,63 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package router
+
+import (
+	"fmt"
+	"log"
+	"net"
+)
+
+const (
+	ROUTE_MODE_STATIC = "static"
+	ROUTE_MODE_BGP    = "bgp"
+)
+
+// RouteSpeaker announces pod CIDRs of this host to BGP peers
+// and installs the learned routes to the table of network
+type RouteSpeaker interface {
+	Announce(name string, tableID int, prefixes []string) error
+	Withdraw(name string) error
+}
+
+// Speaker is set by main when BGP speaker is configured on the host
+var Speaker RouteSpeaker
+
+// advertiseL3Config keeps table and rule of network and announces the local pod CIDRs,
+// routes in the table are owned by the speaker and left unchanged
+func advertiseL3Config(req L3ConfigRequest) RouteUpdateResponse {
+	if Speaker == nil {
+		res_msg := "AdvertiseError no BGP speaker configured;"
+		log.Printf("Failed to advertise L3 config %s; message: %s", req.Name, res_msg)
+		return RouteUpdateResponse{Success: false, Message: res_msg}
+	}
+	for _, prefix := range req.Advertise {
+		if _, _, err := net.ParseCIDR(prefix); err != nil {
+			return RouteUpdateResponse{Success: false, Message: fmt.Sprintf("AdvertiseError %v;", err)}
+		}
+	}
+	tableID, err := GetTableID(req.Name, req.Subnet, true)
+	if err != nil {
+		return RouteUpdateResponse{Success: false, Message: fmt.Sprintf("AdvertiseError %v;", err)}
+	}
+	err = Speaker.Announce(req.Name, tableID, req.Advertise)
+	if err != nil {
+		res_msg := fmt.Sprintf("AdvertiseError %v;", err)
+		log.Printf("Failed to advertise L3 config %s; message: %s", req.Name, res_msg)
+		return RouteUpdateResponse{Success: false, Message: res_msg}
+	}
+	return RouteUpdateResponse{Success: true, Message: fmt.Sprintf("%d prefix(es) advertised", len(req.Advertise))}
+}
+
+// withdrawL3Config stops announcing pod CIDRs of network if BGP speaker is configured
+func withdrawL3Config(name string) {
+	if Speaker == nil {
+		return
+	}
+	if err := Speaker.Withdraw(name); err != nil {
+		log.Printf("failed to withdraw %s: %v", name, err)
+	}
+}
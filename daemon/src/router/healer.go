@@ -0,0 +1,209 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package router
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	DEFAULT_HEAL_INTERVAL = 60 * time.Second
+	HEAL_DEBOUNCE         = 500 * time.Millisecond
+	HEAL_INTERVAL_ENV     = "L3_HEAL_INTERVAL"
+	// reserved tables: default (253), main (254), and local (255)
+	RESERVED_TABLE_MIN = 253
+	RESERVED_TABLE_MAX = 255
+)
+
+// RepairReport is a repair applied to restore the last desired L3 config of a network
+type RepairReport struct {
+	Name          string
+	RuleRestored  bool
+	RouteOutcomes []RouteOutcome
+	Success       bool
+}
+
+// RepairHandler is called for each repair, set by main to report repairs back to the operator
+var RepairHandler func(report RepairReport)
+
+// l3Lock serializes L3 config changes from API requests and self-healing
+var l3Lock sync.Mutex
+
+// l3ConfigCache keeps the last desired L3ConfigRequest per network
+var l3ConfigCache = struct {
+	sync.Mutex
+	requests map[string]L3ConfigRequest
+}{requests: make(map[string]L3ConfigRequest)}
+
+func setL3ConfigCache(req L3ConfigRequest) {
+	req.Force = false
+	l3ConfigCache.Lock()
+	defer l3ConfigCache.Unlock()
+	l3ConfigCache.requests[req.Name] = req
+}
+
+func getL3ConfigCache(name string) (L3ConfigRequest, bool) {
+	l3ConfigCache.Lock()
+	defer l3ConfigCache.Unlock()
+	req, found := l3ConfigCache.requests[name]
+	return req, found
+}
+
+func unsetL3ConfigCache(name string) {
+	l3ConfigCache.Lock()
+	defer l3ConfigCache.Unlock()
+	delete(l3ConfigCache.requests, name)
+}
+
+func listL3ConfigCache() []L3ConfigRequest {
+	l3ConfigCache.Lock()
+	defer l3ConfigCache.Unlock()
+	requests := []L3ConfigRequest{}
+	for _, req := range l3ConfigCache.requests {
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+// GetHealInterval returns self-healing interval from L3_HEAL_INTERVAL (seconds), zero disables periodic check
+func GetHealInterval() time.Duration {
+	setInterval, found := os.LookupEnv(HEAL_INTERVAL_ENV)
+	if found && setInterval != "" {
+		interval, err := strconv.Atoi(setInterval)
+		if err == nil && interval >= 0 {
+			return time.Duration(interval) * time.Second
+		}
+		log.Printf("invalid %s=%s, use default", HEAL_INTERVAL_ENV, setInterval)
+	}
+	return DEFAULT_HEAL_INTERVAL
+}
+
+// StartSelfHealing restores cached L3 configs on route updates of L3 config tables,
+// link updates of masters and overlay devices, and periodically for policy rules
+func StartSelfHealing(interval time.Duration, quit <-chan struct{}) {
+	routeCh := make(chan netlink.RouteUpdate)
+	linkCh := make(chan netlink.LinkUpdate)
+	if err := netlink.RouteSubscribe(routeCh, quit); err != nil {
+		log.Printf("failed to subscribe route updates: %v", err)
+	}
+	if err := netlink.LinkSubscribe(linkCh, quit); err != nil {
+		log.Printf("failed to subscribe link updates: %v", err)
+	}
+	var tickerCh <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickerCh = ticker.C
+	}
+	debounce := time.NewTimer(HEAL_DEBOUNCE)
+	defer debounce.Stop()
+	log.Printf("start self-healing of L3 config (interval: %v)", interval)
+	for {
+		select {
+		case <-quit:
+			return
+		case update, ok := <-routeCh:
+			if !ok {
+				routeCh = nil
+				continue
+			}
+			if isL3ConfigTable(update.Table) {
+				debounce.Reset(HEAL_DEBOUNCE)
+			}
+		case update, ok := <-linkCh:
+			if !ok {
+				linkCh = nil
+				continue
+			}
+			if update.Link != nil && isL3ConfigLink(update.Link.Attrs().Name) {
+				debounce.Reset(HEAL_DEBOUNCE)
+			}
+		case <-tickerCh:
+			HealL3Configs()
+		case <-debounce.C:
+			HealL3Configs()
+		}
+	}
+}
+
+// isL3ConfigTable checks if the table can be assigned to L3 config
+func isL3ConfigTable(tableID int) bool {
+	return tableID >= MIX_TABLE_INDEX && (tableID < RESERVED_TABLE_MIN || tableID > RESERVED_TABLE_MAX)
+}
+
+// isL3ConfigLink checks if the link is a master or an overlay device of any cached L3 config
+func isL3ConfigLink(name string) bool {
+	for _, req := range listL3ConfigCache() {
+		for _, hostRoute := range req.Routes {
+			if hostRoute.InterfaceName == name {
+				return true
+			}
+			for _, nextHop := range hostRoute.NextHops {
+				if nextHop.InterfaceName == name {
+					return true
+				}
+			}
+		}
+		for _, rail := range req.Rails {
+			if rail.InterfaceName == name {
+				return true
+			}
+		}
+		if req.Overlay != nil {
+			for master, vni := range req.Overlay.VNIs {
+				if master == name || getOverlayDeviceName(vni) == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HealL3Configs reconciles each cached L3 config and reports repairs
+func HealL3Configs() []RepairReport {
+	reports := []RepairReport{}
+	for _, req := range listL3ConfigCache() {
+		report, repaired := healL3Config(req)
+		if !repaired {
+			continue
+		}
+		log.Printf("repair L3 config %s: rule restored=%v, %d route(s) changed, success=%v", report.Name, report.RuleRestored, len(report.RouteOutcomes), report.Success)
+		if RepairHandler != nil {
+			RepairHandler(report)
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// healL3Config restores policy rule and routes of the desired L3 config if drifted
+func healL3Config(req L3ConfigRequest) (RepairReport, bool) {
+	l3Lock.Lock()
+	defer l3Lock.Unlock()
+	report := RepairReport{Name: req.Name, Success: true}
+	foundID, err := lookupTableID(req.Name, req.Subnet)
+	ruleExists := err == nil && foundID != -1 && isRuleExist(foundID) && isRailRuleExist(req)
+	response := ReconcileL3Config(req)
+	for _, outcome := range response.Routes {
+		if outcome.Action != RouteUnchanged && outcome.Action != RouteSkipped {
+			report.RouteOutcomes = append(report.RouteOutcomes, outcome)
+			report.Success = report.Success && outcome.Success
+		}
+	}
+	if !ruleExists {
+		tableID, err := lookupTableID(req.Name, req.Subnet)
+		report.RuleRestored = err == nil && tableID != -1 && isRuleExist(tableID) && isRailRuleExist(req)
+		report.Success = report.Success && report.RuleRestored
+	}
+	return report, !ruleExists || len(report.RouteOutcomes) > 0
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package router
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// NextHop is a path of multipath route, weight is 1 if not set
+type NextHop struct {
+	NextHop       string `json:"via"`
+	InterfaceName string `json:"iface"`
+	Weight        int    `json:"weight,omitempty"`
+}
+
+// getMultipathRoute returns multipath route over the next hops with available interface
+// and the link of the first path
+func getMultipathRoute(hostRoute HostRoute, dst *net.IPNet, tableID int) (netlink.Route, netlink.Link, error) {
+	var firstDev netlink.Link
+	paths := []*netlink.NexthopInfo{}
+	for _, nextHop := range hostRoute.NextHops {
+		dev, err := netlink.LinkByName(nextHop.InterfaceName)
+		if err != nil {
+			log.Printf("skip next hop %s of %s: %v", nextHop.InterfaceName, hostRoute.Subnet, err)
+			continue
+		}
+		if firstDev == nil {
+			firstDev = dev
+		}
+		weight := nextHop.Weight
+		if weight < 1 {
+			weight = 1
+		}
+		paths = append(paths, &netlink.NexthopInfo{
+			LinkIndex: dev.Attrs().Index,
+			Gw:        net.ParseIP(nextHop.NextHop),
+			Hops:      weight - 1,
+		})
+	}
+	if len(paths) == 0 {
+		return netlink.Route{}, nil, fmt.Errorf("no available next hop")
+	}
+	if len(paths) == 1 {
+		// single path
+		return netlink.Route{
+			LinkIndex: paths[0].LinkIndex,
+			Scope:     netlink.SCOPE_UNIVERSE,
+			Dst:       dst,
+			Gw:        paths[0].Gw,
+			Table:     tableID,
+			Protocol:  MULTI_NIC_ROUTE_PROTOCOL,
+		}, firstDev, nil
+	}
+	return netlink.Route{
+		Scope:     netlink.SCOPE_UNIVERSE,
+		Dst:       dst,
+		MultiPath: paths,
+		Table:     tableID,
+		Protocol:  MULTI_NIC_ROUTE_PROTOCOL,
+	}, firstDev, nil
+}
+
+// getPathKeys returns sorted keys of link index, gateway, and weight of each path
+func getPathKeys(route netlink.Route) []string {
+	keys := []string{}
+	if len(route.MultiPath) == 0 {
+		return []string{getPathKey(route.LinkIndex, route.Gw, 0)}
+	}
+	for _, path := range route.MultiPath {
+		keys = append(keys, getPathKey(path.LinkIndex, path.Gw, path.Hops))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getPathKey returns key of path, unspecified next hop (0.0.0.0) is stored without gateway
+func getPathKey(linkIndex int, gw net.IP, hops int) string {
+	gwStr := ""
+	if gw != nil && !gw.IsUnspecified() {
+		gwStr = gw.String()
+	}
+	return fmt.Sprintf("%d/%s/%d", linkIndex, gwStr, hops)
+}
+
+// getPathNames returns comma-separated next hops and interface names of route
+func getPathNames(route netlink.Route) (string, string) {
+	type path struct {
+		linkIndex int
+		gw        net.IP
+	}
+	paths := []path{{route.LinkIndex, route.Gw}}
+	if len(route.MultiPath) > 0 {
+		paths = []path{}
+		for _, nexthop := range route.MultiPath {
+			paths = append(paths, path{nexthop.LinkIndex, nexthop.Gw})
+		}
+	}
+	nextHops := []string{}
+	interfaceNames := []string{}
+	for _, p := range paths {
+		if p.gw != nil {
+			nextHops = append(nextHops, p.gw.String())
+		}
+		if link, err := netlink.LinkByIndex(p.linkIndex); err == nil {
+			interfaceNames = append(interfaceNames, link.Attrs().Name)
+		}
+	}
+	return strings.Join(nextHops, ","), strings.Join(interfaceNames, ",")
+}
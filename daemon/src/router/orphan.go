@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package router
+
+import (
+	"log"
+	"sort"
+
+	"github.com/vishvananda/netlink"
+)
+
+// MULTI_NIC_ROUTE_PROTOCOL marks routes programmed by the daemon to identify tables owned by multi-nic
+const MULTI_NIC_ROUTE_PROTOCOL netlink.RouteProtocol = 109
+
+// OrphanL3Config is a table owned by multi-nic of no existing network, removed at startup
+type OrphanL3Config struct {
+	TableID int      `json:"tableID"`
+	Name    string   `json:"name,omitempty"`
+	Subnets []string `json:"subnets,omitempty"`
+	Routes  int      `json:"routes"`
+	Success bool     `json:"success"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// getOwnedTables returns route count of each L3 config table whose routes are all programmed by the daemon
+func getOwnedTables() (map[int]int, error) {
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: 0}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[int]int)
+	foreign := make(map[int]bool)
+	for _, route := range routes {
+		if !isL3ConfigTable(route.Table) {
+			continue
+		}
+		if route.Protocol != MULTI_NIC_ROUTE_PROTOCOL {
+			foreign[route.Table] = true
+			continue
+		}
+		owned[route.Table] += 1
+	}
+	for tableID := range foreign {
+		delete(owned, tableID)
+	}
+	return owned, nil
+}
+
+// CleanOrphanL3Configs removes routes, rules, and rt_tables entry of the owned tables
+// not assigned to any of the existing networks (name to subnet)
+func CleanOrphanL3Configs(networks map[string]string) ([]OrphanL3Config, error) {
+	l3Lock.Lock()
+	defer l3Lock.Unlock()
+	orphans := []OrphanL3Config{}
+	state, err := getTableState()
+	if err != nil {
+		return orphans, err
+	}
+	ownedTables, err := getOwnedTables()
+	if err != nil {
+		return orphans, err
+	}
+	inUse := make(map[int]bool)
+	for name, subnet := range networks {
+		if tableID := state.findTableID(name, subnet); tableID != -1 {
+			inUse[tableID] = true
+		}
+	}
+	tableIDs := []int{}
+	for tableID := range ownedTables {
+		if !inUse[tableID] {
+			tableIDs = append(tableIDs, tableID)
+		}
+	}
+	sort.Ints(tableIDs)
+	for _, tableID := range tableIDs {
+		orphan := OrphanL3Config{
+			TableID: tableID,
+			Name:    state.names[tableID],
+			Subnets: state.ruleSrcs[tableID],
+			Routes:  ownedTables[tableID],
+			Success: true,
+		}
+		if err := deleteRoutes(tableID); err != nil {
+			orphan.Success = false
+			orphan.Error = err.Error()
+		}
+		for range state.ruleSrcs[tableID] {
+			if err := deleteRule(tableID); err != nil {
+				orphan.Success = false
+				orphan.Error = err.Error()
+			}
+		}
+		if orphan.Name != "" {
+			removeTableName(tableID, orphan.Name)
+		}
+		log.Printf("clean orphan table %d (name=%s, rule=%v, %d route(s)): %v %s", tableID, orphan.Name, orphan.Subnets, orphan.Routes, orphan.Success, orphan.Error)
+		orphans = append(orphans, orphan)
+	}
+	return orphans, nil
+}
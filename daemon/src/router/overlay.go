@@ -0,0 +1,270 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package router
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	OVERLAY_VXLAN         = "vxlan"
+	DEFAULT_OVERLAY_PORT  = 4789
+	OVERLAY_DEVICE_PREFIX = "mnvx"
+	// outer IPv4, UDP, VXLAN, and inner ethernet headers
+	VXLAN_OVERHEAD = 50
+)
+
+// OverlayConfig defines overlay devices riding on the masters,
+// routes on a master in VNIs are programmed on its overlay device instead
+type OverlayConfig struct {
+	Type string `json:"type"`
+	Port int    `json:"port,omitempty"`
+	// VNIs maps master interface name to VNI of its overlay device
+	VNIs map[string]int `json:"vnis"`
+}
+
+func getOverlayDeviceName(vni int) string {
+	return fmt.Sprintf("%s%d", OVERLAY_DEVICE_PREFIX, vni)
+}
+
+// getVtepMAC derives MAC address of overlay device from its underlay IP,
+// so that FDB and neighbor entries of peers are computed from CIDR hosts without exchange
+func getVtepMAC(ip net.IP) net.HardwareAddr {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return nil
+	}
+	// locally administered unicast
+	return net.HardwareAddr{0x02, 0x6d, ip4[0], ip4[1], ip4[2], ip4[3]}
+}
+
+// getOverlayRoutes maps routes on masters to overlay devices and returns overlay device name per master
+func getOverlayRoutes(req L3ConfigRequest) ([]HostRoute, map[string]string) {
+	devNames := make(map[string]string)
+	for master, vni := range req.Overlay.VNIs {
+		devNames[master] = getOverlayDeviceName(vni)
+	}
+	routes := []HostRoute{}
+	for _, hostRoute := range req.Routes {
+		if devName, found := devNames[hostRoute.InterfaceName]; found {
+			hostRoute.InterfaceName = devName
+		}
+		nextHops := []NextHop{}
+		for _, nextHop := range hostRoute.NextHops {
+			if devName, found := devNames[nextHop.InterfaceName]; found {
+				nextHop.InterfaceName = devName
+			}
+			nextHops = append(nextHops, nextHop)
+		}
+		if len(hostRoute.NextHops) > 0 {
+			hostRoute.NextHops = nextHops
+		}
+		routes = append(routes, hostRoute)
+	}
+	return routes, devNames
+}
+
+// getOverlayPeers returns underlay IPs of next hops on each overlay device
+func getOverlayPeers(routes []HostRoute, devNames map[string]string) map[string]map[string]bool {
+	peers := make(map[string]map[string]bool)
+	for _, devName := range devNames {
+		peers[devName] = make(map[string]bool)
+	}
+	addPeer := func(devName string, peer string) {
+		if _, found := peers[devName]; found && peer != "" {
+			peers[devName][peer] = true
+		}
+	}
+	for _, hostRoute := range routes {
+		addPeer(hostRoute.InterfaceName, hostRoute.NextHop)
+		for _, nextHop := range hostRoute.NextHops {
+			addPeer(nextHop.InterfaceName, nextHop.NextHop)
+		}
+	}
+	return peers
+}
+
+// applyOverlay ensures overlay devices and their peers of the request,
+// and returns the request with routes on overlay devices and the overlay link indexes
+func applyOverlay(req L3ConfigRequest) (L3ConfigRequest, map[int]bool, error) {
+	overlayIndexes := make(map[int]bool)
+	if req.Overlay.Type != OVERLAY_VXLAN {
+		return req, overlayIndexes, fmt.Errorf("unsupported overlay type %s", req.Overlay.Type)
+	}
+	port := req.Overlay.Port
+	if port == 0 {
+		port = DEFAULT_OVERLAY_PORT
+	}
+	routes, devNames := getOverlayRoutes(req)
+	peers := getOverlayPeers(routes, devNames)
+	// peers of other networks sharing the same overlay device
+	for _, cachedReq := range listL3ConfigCache() {
+		if cachedReq.Name == req.Name || cachedReq.Overlay == nil {
+			continue
+		}
+		cachedRoutes, cachedDevNames := getOverlayRoutes(cachedReq)
+		for devName, devPeers := range getOverlayPeers(cachedRoutes, cachedDevNames) {
+			if _, found := peers[devName]; found {
+				for peer := range devPeers {
+					peers[devName][peer] = true
+				}
+			}
+		}
+	}
+	for master, vni := range req.Overlay.VNIs {
+		dev, err := ensureOverlayDevice(master, vni, port)
+		if err != nil {
+			log.Printf("failed to ensure overlay device %s on %s: %v", getOverlayDeviceName(vni), master, err)
+			continue
+		}
+		overlayIndexes[dev.Attrs().Index] = true
+		syncOverlayPeers(dev, peers[dev.Attrs().Name])
+	}
+	req.Routes = routes
+	return req, overlayIndexes, nil
+}
+
+// ensureOverlayDevice creates or recreates VXLAN device with VNI on the master if not matched
+func ensureOverlayDevice(masterName string, vni int, port int) (netlink.Link, error) {
+	master, err := netlink.LinkByName(masterName)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := netlink.AddrList(master, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no IPv4 address on %s", masterName)
+	}
+	localIP := addrs[0].IP
+	name := getOverlayDeviceName(vni)
+	if link, err := netlink.LinkByName(name); err == nil {
+		if vxlan, ok := link.(*netlink.Vxlan); ok && vxlan.VxlanId == vni && vxlan.VtepDevIndex == master.Attrs().Index && vxlan.SrcAddr.Equal(localIP) && vxlan.Port == port {
+			return link, netlink.LinkSetUp(link)
+		}
+		log.Printf("recreate overlay device %s on %s", name, masterName)
+		if err = netlink.LinkDel(link); err != nil {
+			return nil, err
+		}
+	}
+	vxlan := &netlink.Vxlan{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:         name,
+			MTU:          master.Attrs().MTU - VXLAN_OVERHEAD,
+			HardwareAddr: getVtepMAC(localIP),
+		},
+		VxlanId:      vni,
+		VtepDevIndex: master.Attrs().Index,
+		SrcAddr:      localIP,
+		Port:         port,
+	}
+	if err = netlink.LinkAdd(vxlan); err != nil {
+		return nil, err
+	}
+	log.Printf("add overlay device %s (vni %d) on %s", name, vni, masterName)
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return link, netlink.LinkSetUp(link)
+}
+
+// syncOverlayPeers keeps permanent neighbor and FDB entries of the overlay device to the peers
+func syncOverlayPeers(dev netlink.Link, peers map[string]bool) {
+	index := dev.Attrs().Index
+	for _, family := range []int{netlink.FAMILY_V4, syscall.AF_BRIDGE} {
+		neighs, err := netlink.NeighList(index, family)
+		if err != nil {
+			log.Printf("failed to list neighbors of %s: %v", dev.Attrs().Name, err)
+			continue
+		}
+		for _, neigh := range neighs {
+			if neigh.State&netlink.NUD_PERMANENT == 0 || neigh.IP == nil || neigh.IP.IsUnspecified() || peers[neigh.IP.String()] {
+				continue
+			}
+			neigh := neigh
+			if err := netlink.NeighDel(&neigh); err != nil {
+				log.Printf("failed to delete neighbor %s of %s: %v", neigh.IP, dev.Attrs().Name, err)
+			}
+		}
+	}
+	for peer := range peers {
+		peerIP := net.ParseIP(peer)
+		mac := getVtepMAC(peerIP)
+		if mac == nil {
+			continue
+		}
+		neigh := &netlink.Neigh{
+			LinkIndex:    index,
+			Family:       netlink.FAMILY_V4,
+			State:        netlink.NUD_PERMANENT,
+			IP:           peerIP,
+			HardwareAddr: mac,
+		}
+		if err := netlink.NeighSet(neigh); err != nil {
+			log.Printf("failed to set neighbor %s of %s: %v", peer, dev.Attrs().Name, err)
+		}
+		fdb := &netlink.Neigh{
+			LinkIndex:    index,
+			Family:       syscall.AF_BRIDGE,
+			Flags:        netlink.NTF_SELF,
+			State:        netlink.NUD_PERMANENT,
+			IP:           peerIP,
+			HardwareAddr: mac,
+		}
+		if err := netlink.NeighSet(fdb); err != nil {
+			log.Printf("failed to set fdb %s of %s: %v", peer, dev.Attrs().Name, err)
+		}
+	}
+}
+
+// setOnlink marks routes via overlay devices as onlink since overlay devices have no address of the underlay
+func setOnlink(devRoutesMap map[netlink.Link][]netlink.Route, overlayIndexes map[int]bool) {
+	for dev, routes := range devRoutesMap {
+		for i := range routes {
+			if overlayIndexes[routes[i].LinkIndex] {
+				routes[i].Flags = int(netlink.FLAG_ONLINK)
+			}
+			for _, path := range routes[i].MultiPath {
+				if overlayIndexes[path.LinkIndex] {
+					path.Flags = int(netlink.FLAG_ONLINK)
+				}
+			}
+		}
+		devRoutesMap[dev] = routes
+	}
+}
+
+// deleteOverlayDevices deletes overlay devices of the request not used by other cached networks
+func deleteOverlayDevices(req L3ConfigRequest) {
+	if req.Overlay == nil {
+		return
+	}
+	inUse := make(map[int]bool)
+	for _, cachedReq := range listL3ConfigCache() {
+		if cachedReq.Name == req.Name || cachedReq.Overlay == nil {
+			continue
+		}
+		for _, vni := range cachedReq.Overlay.VNIs {
+			inUse[vni] = true
+		}
+	}
+	for _, vni := range req.Overlay.VNIs {
+		if inUse[vni] {
+			continue
+		}
+		if link, err := netlink.LinkByName(getOverlayDeviceName(vni)); err == nil {
+			err = netlink.LinkDel(link)
+			log.Printf("delete overlay device %s: %v", link.Attrs().Name, err)
+		}
+	}
+}
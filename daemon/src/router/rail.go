@@ -0,0 +1,160 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package router
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+// RAIL_RULE_PRIORITY places rules of rails before the network rules added with default priority
+const RAIL_RULE_PRIORITY = 1000
+
+// RailConfig is an interface index of network with its own table,
+// traffic from VLAN CIDR of the index looks up routes via its master only
+type RailConfig struct {
+	Index         int    `json:"index"`
+	Subnet        string `json:"subnet"`
+	InterfaceName string `json:"iface"`
+}
+
+// GetRailTableName returns table name of the interface index of network
+func GetRailTableName(name string, index int) string {
+	return fmt.Sprintf("%s-rail%d", name, index)
+}
+
+// getRailRequest returns request of the rail table with routes via the rail interface,
+// multipath routes are reduced to the next hop via the rail interface
+func getRailRequest(req L3ConfigRequest, rail RailConfig) L3ConfigRequest {
+	ifaceName := rail.InterfaceName
+	if req.Overlay != nil {
+		if vni, found := req.Overlay.VNIs[rail.InterfaceName]; found {
+			ifaceName = getOverlayDeviceName(vni)
+		}
+	}
+	routes := []HostRoute{}
+	for _, hostRoute := range req.Routes {
+		if len(hostRoute.NextHops) == 0 {
+			if hostRoute.InterfaceName == ifaceName {
+				routes = append(routes, hostRoute)
+			}
+			continue
+		}
+		for _, nextHop := range hostRoute.NextHops {
+			if nextHop.InterfaceName == ifaceName {
+				routes = append(routes, HostRoute{Subnet: hostRoute.Subnet, NextHop: nextHop.NextHop, InterfaceName: ifaceName})
+				break
+			}
+		}
+	}
+	return L3ConfigRequest{
+		Name:   GetRailTableName(req.Name, rail.Index),
+		Subnet: rail.Subnet,
+		Routes: routes,
+	}
+}
+
+// reconcileRails reconciles table and rule of each rail and deletes tables of rails no longer requested
+func reconcileRails(req L3ConfigRequest, overlayIndexes map[int]bool) []RouteOutcome {
+	if req.Force {
+		deleteRailTables(req.Name, req.Rails)
+	}
+	if prevReq, found := getL3ConfigCache(req.Name); found {
+		requested := make(map[RailConfig]bool)
+		for _, rail := range req.Rails {
+			requested[RailConfig{Index: rail.Index, Subnet: rail.Subnet}] = true
+		}
+		staleRails := []RailConfig{}
+		for _, rail := range prevReq.Rails {
+			if !requested[RailConfig{Index: rail.Index, Subnet: rail.Subnet}] {
+				staleRails = append(staleRails, rail)
+			}
+		}
+		deleteRailTables(req.Name, staleRails)
+	}
+	outcomes := []RouteOutcome{}
+	for _, rail := range req.Rails {
+		railReq := getRailRequest(req, rail)
+		if _, err := getRailTableID(railReq.Name, railReq.Subnet); err != nil {
+			log.Printf("failed to get table of %s: %v", railReq.Name, err)
+			outcomes = append(outcomes, RouteOutcome{Subnet: rail.Subnet, InterfaceName: rail.InterfaceName, Action: RouteSkipped, Success: false, Error: err.Error()})
+			continue
+		}
+		railOutcomes, err := reconcileTable(railReq, overlayIndexes)
+		if err != nil {
+			log.Printf("failed to apply L3 config %s; message: %v", railReq.Name, err)
+			outcomes = append(outcomes, RouteOutcome{Subnet: rail.Subnet, InterfaceName: rail.InterfaceName, Action: RouteSkipped, Success: false, Error: err.Error()})
+			continue
+		}
+		for _, outcome := range railOutcomes {
+			// unresolved routes are already reported by the network table
+			if outcome.Action != RouteSkipped {
+				outcomes = append(outcomes, outcome)
+			}
+		}
+	}
+	return outcomes
+}
+
+// getRailTableID returns table ID of the rail and adds its rule with RAIL_RULE_PRIORITY if not exists
+func getRailTableID(tableName string, subnet string) (int, error) {
+	state, err := getTableState()
+	if err != nil {
+		return -1, err
+	}
+	tableID := state.findTableID(tableName, subnet)
+	if tableID == -1 {
+		tableID = state.allocateTableID(tableName, subnet)
+		if tableID == -1 {
+			return tableID, errors.New("No available ID")
+		}
+		log.Printf("allocate table %d to %s", tableID, tableName)
+		writeTableName(tableID, tableName)
+	}
+	if !isRuleExist(tableID) {
+		_, src, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return -1, err
+		}
+		rule := netlink.NewRule()
+		rule.Src = src
+		rule.Table = tableID
+		rule.Priority = RAIL_RULE_PRIORITY
+		err = netlink.RuleAdd(rule)
+		log.Printf("add rule %v:%v", rule, err)
+		if err != nil {
+			return -1, err
+		}
+	}
+	return tableID, nil
+}
+
+// isRailRuleExist checks if rules of all rails of the request exist
+func isRailRuleExist(req L3ConfigRequest) bool {
+	for _, rail := range req.Rails {
+		tableID, err := lookupTableID(GetRailTableName(req.Name, rail.Index), rail.Subnet)
+		if err != nil || tableID == -1 || !isRuleExist(tableID) {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteRailTables deletes routes, rule, and table name of the rails
+func deleteRailTables(name string, rails []RailConfig) {
+	for _, rail := range rails {
+		tableName := GetRailTableName(name, rail.Index)
+		tableID, err := lookupTableID(tableName, rail.Subnet)
+		if err != nil || tableID == -1 {
+			continue
+		}
+		deleteL3Config(tableName, tableID)
+	}
+}
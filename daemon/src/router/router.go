@@ -12,6 +12,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"sort"
 
 	"github.com/vishvananda/netlink"
 )
@@ -22,58 +23,260 @@ type L3ConfigRequest struct {
 	Subnet string      `json:"subnet"`
 	Routes []HostRoute `json:"routes"`
 	Force  bool        `json:"force"`
+	// RouteMode is static (default) or bgp to announce Advertise by Speaker instead of programming Routes
+	RouteMode string   `json:"routeMode,omitempty"`
+	Advertise []string `json:"advertise,omitempty"`
+	// Overlay programs routes on overlay devices riding on the masters if set
+	Overlay *OverlayConfig `json:"overlay,omitempty"`
+	// Rails adds a table and source rule per interface index in addition to the network table if set
+	Rails []RailConfig `json:"rails,omitempty"`
 }
 
+// HostRoute is a route to the subnet via the next hop,
+// multipath route over NextHops if set
 type HostRoute struct {
-	Subnet        string `json:"net"`
-	NextHop       string `json:"via"`
-	InterfaceName string `json:"iface"`
+	Subnet        string    `json:"net"`
+	NextHop       string    `json:"via"`
+	InterfaceName string    `json:"iface"`
+	NextHops      []NextHop `json:"nexthops,omitempty"`
 }
 type RouteUpdateResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"msg"`
+	Success bool           `json:"success"`
+	Message string         `json:"msg"`
+	Routes  []RouteOutcome `json:"routes,omitempty"`
+}
+
+// RouteAction is an action taken on a route to reach the desired route set of table
+type RouteAction string
+
+const (
+	RouteAdded     RouteAction = "add"
+	RouteReplaced  RouteAction = "replace"
+	RouteDeleted   RouteAction = "delete"
+	RouteUnchanged RouteAction = "unchanged"
+	RouteSkipped   RouteAction = "skip"
+)
+
+// RouteOutcome is an outcome of reconciling a single route
+type RouteOutcome struct {
+	Subnet        string      `json:"net"`
+	NextHop       string      `json:"via,omitempty"`
+	InterfaceName string      `json:"iface,omitempty"`
+	Action        RouteAction `json:"action"`
+	Success       bool        `json:"success"`
+	Error         string      `json:"error,omitempty"`
 }
 
 func ApplyL3Config(r *http.Request) RouteUpdateResponse {
-	res_msg := ""
-	success := true
-	_, tableID, devRoutesMap, err := getRoutesFromRequest(r, true)
-	if err == nil {
-		for dev, routes := range devRoutesMap {
-			for _, route := range routes {
-				exists, err := isRouteExist(route, dev)
-				if err != nil {
-					log.Printf("Failed to check route %s exists: %v", route.String(), err)
-				}
-				if !exists {
-					log.Printf("Add route %s; (%v)", route.String(), exists)
-					err = netlink.RouteAdd(&route)
-					if err != nil {
-						res_msg += fmt.Sprintf("AddRouteError %v;", err)
-						success = false
-					} else {
-						res_msg += fmt.Sprintf("Add route %s;", route.String())
-						success = success && true
-					}
-				} else {
-					res_msg += fmt.Sprintf("%s route exists;", route.String())
-				}
-			}
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return RouteUpdateResponse{Success: false, Message: fmt.Sprintf("AddRoutesError %v;", err)}
+	}
+	var req L3ConfigRequest
+	err = json.Unmarshal(reqBody, &req)
+	if err != nil {
+		return RouteUpdateResponse{Success: false, Message: fmt.Sprintf("AddRoutesError %v;", err)}
+	}
+	l3Lock.Lock()
+	defer l3Lock.Unlock()
+	response := ReconcileL3Config(req)
+	setL3ConfigCache(req)
+	return response
+}
+
+// ReconcileL3Config computes the desired route set of the table and applies only adds, replaces, and deletes
+func ReconcileL3Config(req L3ConfigRequest) RouteUpdateResponse {
+	if req.RouteMode == ROUTE_MODE_BGP {
+		return advertiseL3Config(req)
+	}
+	overlayIndexes := make(map[int]bool)
+	if req.Overlay != nil {
+		var err error
+		req, overlayIndexes, err = applyOverlay(req)
+		if err != nil {
+			res_msg := fmt.Sprintf("OverlayError %v;", err)
+			log.Printf("Failed to apply L3 config %s; message: %s", req.Name, res_msg)
+			return RouteUpdateResponse{Success: false, Message: res_msg}
 		}
-	} else {
-		res_msg += fmt.Sprintf("AddRoutesError %v;", err)
-		success = false
 	}
-	if !success {
-		log.Printf("Failed to apply L3 config %d; message: %s (%v)", tableID, res_msg, success)
+	outcomes, err := reconcileTable(req, overlayIndexes)
+	if err != nil {
+		res_msg := err.Error()
+		log.Printf("Failed to apply L3 config %s; message: %s", req.Name, res_msg)
+		return RouteUpdateResponse{Success: false, Message: res_msg}
+	}
+	outcomes = append(outcomes, reconcileRails(req, overlayIndexes)...)
+	response := newRouteUpdateResponse(outcomes)
+	if !response.Success {
+		log.Printf("Failed to apply L3 config %s; message: %s", req.Name, response.Message)
 	}
-	response := RouteUpdateResponse{Success: success, Message: res_msg}
 	return response
 }
 
+// reconcileTable reconciles routes in the table of the request
+func reconcileTable(req L3ConfigRequest, overlayIndexes map[int]bool) ([]RouteOutcome, error) {
+	_, tableID, devRoutesMap, err := getRoutesFromL3Config(req, true)
+	if err != nil {
+		return nil, fmt.Errorf("AddRoutesError %v;", err)
+	}
+	setOnlink(devRoutesMap, overlayIndexes)
+	existingRoutes, err := GetRoutes(tableID)
+	if err != nil {
+		return nil, fmt.Errorf("GetRoutesError %v;", err)
+	}
+	desiredRoutes, outcomes := getDesiredRoutes(req, devRoutesMap)
+	return append(outcomes, reconcileRoutes(desiredRoutes, existingRoutes)...), nil
+}
+
+// getDesiredRoutes returns desired routes keyed by destination
+// and skipped outcomes of the requested routes that cannot be resolved
+func getDesiredRoutes(req L3ConfigRequest, devRoutesMap map[netlink.Link][]netlink.Route) (map[string]netlink.Route, []RouteOutcome) {
+	desiredRoutes := make(map[string]netlink.Route)
+	for _, routes := range devRoutesMap {
+		for _, route := range routes {
+			if route.Dst == nil {
+				continue
+			}
+			desiredRoutes[route.Dst.String()] = route
+		}
+	}
+	outcomes := []RouteOutcome{}
+	for _, hostRoute := range req.Routes {
+		_, dst, err := net.ParseCIDR(hostRoute.Subnet)
+		if err == nil {
+			if _, found := desiredRoutes[dst.String()]; found {
+				continue
+			}
+			err = fmt.Errorf("interface %s not found", hostRoute.InterfaceName)
+			if len(hostRoute.NextHops) > 0 {
+				err = fmt.Errorf("no available next hop")
+			}
+		}
+		outcomes = append(outcomes, RouteOutcome{
+			Subnet:        hostRoute.Subnet,
+			NextHop:       hostRoute.NextHop,
+			InterfaceName: hostRoute.InterfaceName,
+			Action:        RouteSkipped,
+			Success:       false,
+			Error:         err.Error(),
+		})
+	}
+	return desiredRoutes, outcomes
+}
+
+// reconcileRoutes adds missing routes, replaces routes with changed next hop or interface,
+// and deletes routes not in the desired set
+func reconcileRoutes(desiredRoutes map[string]netlink.Route, existingRoutes []netlink.Route) []RouteOutcome {
+	outcomes := []RouteOutcome{}
+	existingRouteMap := make(map[string]netlink.Route)
+	for _, route := range existingRoutes {
+		if route.Dst == nil {
+			continue
+		}
+		existingRouteMap[route.Dst.String()] = route
+	}
+	dsts := []string{}
+	for dst := range desiredRoutes {
+		dsts = append(dsts, dst)
+	}
+	sort.Strings(dsts)
+	for _, dst := range dsts {
+		route := desiredRoutes[dst]
+		action := RouteAdded
+		if existingRoute, found := existingRouteMap[dst]; found {
+			if isSameRoute(existingRoute, route) {
+				outcomes = append(outcomes, newRouteOutcome(route, RouteUnchanged, nil))
+				continue
+			}
+			action = RouteReplaced
+		}
+		var err error
+		if action == RouteAdded {
+			err = netlink.RouteAdd(&route)
+		} else {
+			err = netlink.RouteReplace(&route)
+		}
+		log.Printf("%s route %s: %v", action, route.String(), err)
+		outcomes = append(outcomes, newRouteOutcome(route, action, err))
+	}
+	dsts = []string{}
+	for dst := range existingRouteMap {
+		if _, found := desiredRoutes[dst]; !found {
+			dsts = append(dsts, dst)
+		}
+	}
+	sort.Strings(dsts)
+	for _, dst := range dsts {
+		route := existingRouteMap[dst]
+		err := netlink.RouteDel(&route)
+		log.Printf("%s route %s: %v", RouteDeleted, route.String(), err)
+		outcomes = append(outcomes, newRouteOutcome(route, RouteDeleted, err))
+	}
+	return outcomes
+}
+
+// isSameRoute checks if the routes have the same paths of next hop, interface, and weight
+func isSameRoute(route, cmpRoute netlink.Route) bool {
+	if route.Protocol != cmpRoute.Protocol {
+		return false
+	}
+	keys := getPathKeys(route)
+	cmpKeys := getPathKeys(cmpRoute)
+	if len(keys) != len(cmpKeys) {
+		return false
+	}
+	for index, key := range keys {
+		if key != cmpKeys[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func newRouteOutcome(route netlink.Route, action RouteAction, err error) RouteOutcome {
+	outcome := RouteOutcome{
+		Subnet:  route.Dst.String(),
+		Action:  action,
+		Success: err == nil,
+	}
+	outcome.NextHop, outcome.InterfaceName = getPathNames(route)
+	if err != nil {
+		outcome.Error = err.Error()
+	}
+	return outcome
+}
+
+// newRouteUpdateResponse summarizes route outcomes
+func newRouteUpdateResponse(outcomes []RouteOutcome) RouteUpdateResponse {
+	countMap := make(map[RouteAction]int)
+	failed := 0
+	for _, outcome := range outcomes {
+		if outcome.Success {
+			countMap[outcome.Action] += 1
+		} else {
+			failed += 1
+		}
+	}
+	msg := fmt.Sprintf("%d added, %d replaced, %d deleted, %d unchanged, %d failed", countMap[RouteAdded], countMap[RouteReplaced], countMap[RouteDeleted], countMap[RouteUnchanged], failed)
+	return RouteUpdateResponse{
+		Success: failed == 0,
+		Message: msg,
+		Routes:  outcomes,
+	}
+}
+
 func DeleteL3Config(r *http.Request) RouteUpdateResponse {
+	l3Lock.Lock()
+	defer l3Lock.Unlock()
 	tableName, tableID, _, _ := getRoutesFromRequest(r, false)
+	cachedReq, cached := getL3ConfigCache(tableName)
+	unsetL3ConfigCache(tableName)
+	withdrawL3Config(tableName)
 	success, res_msg := deleteL3Config(tableName, tableID)
+	if cached {
+		deleteRailTables(cachedReq.Name, cachedReq.Rails)
+		deleteOverlayDevices(cachedReq)
+	}
 	response := RouteUpdateResponse{Success: success, Message: res_msg}
 	return response
 }
@@ -212,6 +415,15 @@ func getRoutesFromL3Config(req L3ConfigRequest, addIfNotExists bool) (string, in
 	}
 
 	for _, hostRoute := range req.Routes {
+		if len(hostRoute.NextHops) > 0 {
+			_, dst, _ := net.ParseCIDR(hostRoute.Subnet)
+			route, dev, err := getMultipathRoute(hostRoute, dst, tableID)
+			if err != nil {
+				continue
+			}
+			devRoutesMap[dev] = append(devRoutesMap[dev], route)
+			continue
+		}
 		dev, err := netlink.LinkByName(hostRoute.InterfaceName)
 		if err != nil {
 			continue
@@ -227,6 +439,7 @@ func getRoutesFromL3Config(req L3ConfigRequest, addIfNotExists bool) (string, in
 			Dst:       dst,
 			Gw:        nextHop,
 			Table:     tableID,
+			Protocol:  MULTI_NIC_ROUTE_PROTOCOL,
 		}
 		devRoutesMap[dev] = append(devRoutesMap[dev], route)
 	}
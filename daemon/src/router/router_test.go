@@ -8,6 +8,7 @@ package router
 import (
 	"bytes"
 	"encoding/json"
+	"net"
 	"net/http"
 	"testing"
 
@@ -15,6 +16,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"os"
+	"path/filepath"
 
 	"github.com/vishvananda/netlink"
 )
@@ -57,6 +59,55 @@ var _ = Describe("Test Route", Ordered, func() {
 		})
 	})
 
+	Context("Table ID", func() {
+		It("derives table ID from name and skips claimed tables", func() {
+			candidates := getTableIDCandidates("net-a")
+			Expect(candidates).NotTo(BeEmpty())
+			Expect(getTableIDCandidates("net-a")).To(Equal(candidates))
+			state := &tableState{
+				ruleSrcs:      map[int][]string{candidates[0]: {"10.0.0.0/16"}},
+				ownedRoutes:   map[int]int{},
+				foreignRoutes: map[int]bool{},
+				names:         map[int]string{},
+				nameIDs:       map[string]int{},
+			}
+			Expect(state.findTableID("net-a", "192.168.0.0/16")).To(Equal(-1))
+			Expect(state.allocateTableID("net-a", "192.168.0.0/16")).To(Equal(candidates[1]))
+			state.ruleSrcs[candidates[1]] = []string{"192.168.0.0/16"}
+			Expect(state.findTableID("net-a", "192.168.0.0/16")).To(Equal(candidates[1]))
+			By("keeping table with owned routes but without rule")
+			delete(state.ruleSrcs, candidates[1])
+			state.ownedRoutes[candidates[1]] = 1
+			Expect(state.findTableID("net-a", "192.168.0.0/16")).To(Equal(candidates[1]))
+			By("keeping table with owned routes of previous subnet")
+			state.ruleSrcs[candidates[1]] = []string{"192.168.0.0/16"}
+			Expect(state.findTableID("net-a", "172.16.0.0/16")).To(Equal(candidates[1]))
+			By("skipping table with foreign routes")
+			state.foreignRoutes[candidates[1]] = true
+			Expect(state.findTableID("net-a", "192.168.0.0/16")).To(Equal(-1))
+			delete(state.foreignRoutes, candidates[1])
+			By("using legacy name in rt_tables")
+			state.nameIDs["net-a"] = 101
+			Expect(state.findTableID("net-a", "192.168.0.0/16")).To(Equal(101))
+		})
+
+		It("writes rt_tables only if enabled", func() {
+			tmpPath := filepath.Join(GinkgoT().TempDir(), "rt_tables")
+			content := "255\tlocal\n"
+			Expect(os.WriteFile(tmpPath, []byte(content), 0644)).To(Succeed())
+			RT_TABLE_PATH = tmpPath
+			defer SetRTTablePath()
+			RT_TABLE_WRITE = false
+			writeTableName(1000, "net-a")
+			Expect(os.ReadFile(tmpPath)).To(BeEquivalentTo(content))
+			RT_TABLE_WRITE = true
+			writeTableName(1000, "net-a")
+			Expect(os.ReadFile(tmpPath)).To(BeEquivalentTo(content + "1000\tnet-a\n"))
+			removeTableName(1000, "net-a")
+			Expect(os.ReadFile(tmpPath)).To(BeEquivalentTo(content))
+		})
+	})
+
 	Context("Table", func() {
 		var tableID int
 		var dst = "192.168.0.0/24"
@@ -137,6 +188,386 @@ var _ = Describe("Test Route", Ordered, func() {
 				Expect(reqName).To(Equal(testTableName))
 			})
 		})
+
+		It("ReconcileL3Config", func() {
+			newDst := "192.168.2.0/24"
+			req := L3ConfigRequest{
+				Name:   testTableName,
+				Subnet: "192.168.0.0/16",
+				Routes: []HostRoute{route},
+			}
+			response := ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes).To(HaveLen(1))
+			Expect(response.Routes[0].Action).To(Equal(RouteAdded))
+
+			By("applying the same routes")
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes).To(HaveLen(1))
+			Expect(response.Routes[0].Action).To(Equal(RouteUnchanged))
+
+			By("replacing stale route")
+			req.Routes = []HostRoute{{Subnet: newDst, NextHop: route.NextHop, InterfaceName: route.InterfaceName}}
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes).To(HaveLen(2))
+			Expect(response.Routes[0].Action).To(Equal(RouteAdded))
+			Expect(response.Routes[0].Subnet).To(Equal(newDst))
+			Expect(response.Routes[1].Action).To(Equal(RouteDeleted))
+			Expect(response.Routes[1].Subnet).To(Equal(dst))
+			routes, err := GetRoutes(tableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+
+			By("skipping route on unknown interface")
+			req.Routes = append(req.Routes, HostRoute{Subnet: dst, NextHop: route.NextHop, InterfaceName: "notexist"})
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeFalse())
+			Expect(response.Routes).To(HaveLen(2))
+			Expect(response.Routes[0].Action).To(Equal(RouteSkipped))
+			Expect(response.Routes[1].Action).To(Equal(RouteUnchanged))
+		})
+	})
+
+	Context("Multipath", Ordered, func() {
+		var testTableName = "multipathtable"
+
+		AfterAll(func() {
+			tableID, err := GetTableID(testTableName, "192.168.0.0/16", false)
+			Expect(err).NotTo(HaveOccurred())
+			DeleteTable(testTableName, tableID)
+		})
+
+		It("reconciles multipath route", func() {
+			iface := getValidIface()
+			link, err := netlink.LinkByName(iface)
+			Expect(err).NotTo(HaveOccurred())
+			addrs, err := netlink.AddrList(link, netlink.FAMILY_V4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addrs).NotTo(BeEmpty())
+			// gateways on the same link
+			gateways := []string{}
+			for offset := byte(1); len(gateways) < 2; offset++ {
+				gw := addrs[0].IPNet.IP.Mask(addrs[0].IPNet.Mask).To4()
+				gw[3] += offset
+				if !gw.Equal(addrs[0].IPNet.IP) {
+					gateways = append(gateways, gw.String())
+				}
+			}
+			req := L3ConfigRequest{
+				Name:   testTableName,
+				Subnet: "192.168.0.0/16",
+				Routes: []HostRoute{{
+					Subnet: "192.168.5.0/24",
+					NextHops: []NextHop{
+						{NextHop: gateways[0], InterfaceName: iface},
+						{NextHop: gateways[1], InterfaceName: iface, Weight: 2},
+					},
+				}},
+			}
+			response := ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes).To(HaveLen(1))
+			Expect(response.Routes[0].Action).To(Equal(RouteAdded))
+			Expect(response.Routes[0].InterfaceName).To(Equal(iface + "," + iface))
+
+			By("applying the same routes")
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes[0].Action).To(Equal(RouteUnchanged))
+
+			By("changing weight")
+			req.Routes[0].NextHops[1].Weight = 1
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes[0].Action).To(Equal(RouteReplaced))
+
+			By("skipping unavailable next hop")
+			req.Routes[0].NextHops[1].InterfaceName = "notexist"
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes[0].Action).To(Equal(RouteReplaced))
+			Expect(response.Routes[0].InterfaceName).To(Equal(iface))
+		})
+	})
+
+	Context("Rails", Ordered, func() {
+		var testTableName = "railtable"
+		var req L3ConfigRequest
+
+		AfterAll(func() {
+			unsetL3ConfigCache(testTableName)
+			deleteRailTables(testTableName, req.Rails)
+			tableID, err := GetTableID(testTableName, req.Subnet, false)
+			Expect(err).NotTo(HaveOccurred())
+			DeleteTable(testTableName, tableID)
+		})
+
+		It("adds table and source rule per interface index", func() {
+			iface := getValidIface()
+			req = L3ConfigRequest{
+				Name:   testTableName,
+				Subnet: "192.168.0.0/16",
+				Routes: []HostRoute{
+					{Subnet: "192.168.1.0/24", NextHop: "0.0.0.0", InterfaceName: iface},
+					{Subnet: "192.168.65.0/24", NextHop: "0.0.0.0", InterfaceName: "notexist"},
+				},
+				Rails: []RailConfig{
+					{Index: 0, Subnet: "192.168.0.0/18", InterfaceName: iface},
+					{Index: 1, Subnet: "192.168.64.0/18", InterfaceName: "notexist"},
+				},
+			}
+			response := ApplyL3Config(httpL3RequestFromConfig(req))
+			// route via not-existing interface is skipped once
+			Expect(response.Success).To(BeFalse())
+			Expect(response.Message).To(Equal("2 added, 0 replaced, 0 deleted, 0 unchanged, 1 failed"))
+			railTableID, err := lookupTableID(GetRailTableName(testTableName, 0), "192.168.0.0/18")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(railTableID).NotTo(Equal(-1))
+			routes, err := GetRoutes(railTableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+			Expect(routes[0].Dst.String()).To(Equal("192.168.1.0/24"))
+			rules, err := netlink.RuleList(netlink.FAMILY_V4)
+			Expect(err).NotTo(HaveOccurred())
+			found := false
+			for _, rule := range rules {
+				if rule.Table == railTableID {
+					found = true
+					Expect(rule.Src.String()).To(Equal("192.168.0.0/18"))
+					Expect(rule.Priority).To(Equal(RAIL_RULE_PRIORITY))
+				}
+			}
+			Expect(found).To(BeTrue())
+			Expect(isRailRuleExist(req)).To(BeTrue())
+
+			By("removing rail no longer requested")
+			req.Rails = req.Rails[1:]
+			ApplyL3Config(httpL3RequestFromConfig(req))
+			Expect(isRuleExist(railTableID)).To(BeFalse())
+			routes, err = GetRoutes(railTableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(BeEmpty())
+		})
+	})
+
+	Context("BGP mode", Ordered, func() {
+		var testTableName = "bgptable"
+		var speaker *fakeSpeaker
+
+		BeforeAll(func() {
+			speaker = &fakeSpeaker{announced: make(map[string][]string)}
+			Speaker = speaker
+		})
+
+		AfterAll(func() {
+			Speaker = nil
+			tableID, err := GetTableID(testTableName, "192.168.0.0/16", false)
+			Expect(err).NotTo(HaveOccurred())
+			DeleteTable(testTableName, tableID)
+		})
+
+		It("announces local pod CIDRs and keeps learned routes", func() {
+			req := L3ConfigRequest{
+				Name:      testTableName,
+				Subnet:    "192.168.0.0/16",
+				RouteMode: ROUTE_MODE_BGP,
+				Advertise: []string{"192.168.1.0/24"},
+			}
+			response := ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			Expect(speaker.announced[testTableName]).To(Equal(req.Advertise))
+			tableID, err := GetTableID(testTableName, req.Subnet, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tableID).NotTo(Equal(-1))
+			Expect(isRuleExist(tableID)).To(BeTrue())
+
+			By("installing learned route")
+			learned := HostRoute{Subnet: "192.168.2.0/24", NextHop: "0.0.0.0", InterfaceName: getValidIface()}
+			_, _, devRoutesMap, err := getRoutesFromL3Config(L3ConfigRequest{Name: testTableName, Subnet: req.Subnet, Routes: []HostRoute{learned}}, false)
+			Expect(err).NotTo(HaveOccurred())
+			for _, routes := range devRoutesMap {
+				for _, route := range routes {
+					Expect(netlink.RouteAdd(&route)).To(Succeed())
+				}
+			}
+			response = ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			routes, err := GetRoutes(tableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+
+			By("withdrawing on delete")
+			success, _ := deleteL3Config(testTableName, tableID)
+			Expect(success).To(BeTrue())
+			withdrawL3Config(testTableName)
+			Expect(speaker.announced).NotTo(HaveKey(testTableName))
+		})
+
+		It("fails without speaker", func() {
+			Speaker = nil
+			response := ReconcileL3Config(L3ConfigRequest{Name: testTableName, Subnet: "192.168.0.0/16", RouteMode: ROUTE_MODE_BGP})
+			Expect(response.Success).To(BeFalse())
+		})
+	})
+
+	Context("Overlay", Ordered, func() {
+		var testTableName = "overlaytable"
+		var vni = 4242
+
+		It("programs routes on VXLAN device with peers", func() {
+			iface := getValidIface()
+			req := L3ConfigRequest{
+				Name:   testTableName,
+				Subnet: "192.168.0.0/16",
+				Routes: []HostRoute{{Subnet: "192.168.7.0/24", NextHop: "10.99.0.2", InterfaceName: iface}},
+				Overlay: &OverlayConfig{
+					Type: OVERLAY_VXLAN,
+					VNIs: map[string]int{iface: vni},
+				},
+			}
+			response := ApplyL3Config(httpL3RequestFromConfig(req))
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes).To(HaveLen(1))
+			Expect(response.Routes[0].Action).To(Equal(RouteAdded))
+			Expect(response.Routes[0].InterfaceName).To(Equal(getOverlayDeviceName(vni)))
+
+			link, err := netlink.LinkByName(getOverlayDeviceName(vni))
+			Expect(err).NotTo(HaveOccurred())
+			vxlan, ok := link.(*netlink.Vxlan)
+			Expect(ok).To(BeTrue())
+			Expect(vxlan.VxlanId).To(Equal(vni))
+			Expect(vxlan.Port).To(Equal(DEFAULT_OVERLAY_PORT))
+			neighs, err := netlink.NeighList(link.Attrs().Index, netlink.FAMILY_V4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(neighs).To(HaveLen(1))
+			Expect(neighs[0].HardwareAddr).To(Equal(getVtepMAC(net.ParseIP("10.99.0.2"))))
+
+			By("applying the same routes")
+			response = ApplyL3Config(httpL3RequestFromConfig(req))
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes[0].Action).To(Equal(RouteUnchanged))
+
+			By("changing peer")
+			req.Routes[0].NextHop = "10.99.0.3"
+			response = ApplyL3Config(httpL3RequestFromConfig(req))
+			Expect(response.Success).To(BeTrue())
+			Expect(response.Routes[0].Action).To(Equal(RouteReplaced))
+			neighs, err = netlink.NeighList(link.Attrs().Index, netlink.FAMILY_V4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(neighs).To(HaveLen(1))
+			Expect(neighs[0].IP.String()).To(Equal("10.99.0.3"))
+
+			By("deleting L3 config")
+			response = DeleteL3Config(httpL3RequestFromConfig(req))
+			Expect(response.Success).To(BeTrue())
+			_, err = netlink.LinkByName(getOverlayDeviceName(vni))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Orphan cleanup", Ordered, func() {
+		var testTableName = "orphantable"
+		var testSubnet = "192.170.0.0/16"
+		var foreignTableID = 4000
+
+		It("removes only owned tables of no existing network", func() {
+			req := L3ConfigRequest{
+				Name:   testTableName,
+				Subnet: testSubnet,
+				Routes: []HostRoute{{Subnet: "192.170.1.0/24", NextHop: "0.0.0.0", InterfaceName: getValidIface()}},
+			}
+			response := ReconcileL3Config(req)
+			Expect(response.Success).To(BeTrue())
+			tableID, err := GetTableID(testTableName, testSubnet, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tableID).NotTo(Equal(-1))
+
+			link, err := netlink.LinkByName(getValidIface())
+			Expect(err).NotTo(HaveOccurred())
+			_, dst, _ := net.ParseCIDR("192.171.0.0/24")
+			foreignRoute := netlink.Route{LinkIndex: link.Attrs().Index, Dst: dst, Table: foreignTableID}
+			Expect(netlink.RouteAdd(&foreignRoute)).To(Succeed())
+			defer netlink.RouteDel(&foreignRoute)
+
+			By("keeping table of existing network")
+			orphans, err := CleanOrphanL3Configs(map[string]string{testTableName: testSubnet})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans).To(BeEmpty())
+
+			By("removing table of deleted network")
+			orphans, err = CleanOrphanL3Configs(map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans).To(HaveLen(1))
+			Expect(orphans[0].TableID).To(Equal(tableID))
+			Expect(orphans[0].Subnets).To(Equal([]string{testSubnet}))
+			Expect(orphans[0].Routes).To(Equal(1))
+			Expect(orphans[0].Success).To(BeTrue())
+			Expect(isRuleExist(tableID)).To(BeFalse())
+			routes, err := GetRoutes(tableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(BeEmpty())
+			routes, err = GetRoutes(foreignTableID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+		})
+	})
+
+	Context("Self-healing", Ordered, func() {
+		var testTableName = "healtable"
+		var req L3ConfigRequest
+
+		BeforeAll(func() {
+			req = L3ConfigRequest{
+				Name:   testTableName,
+				Subnet: "192.168.0.0/16",
+				Routes: []HostRoute{{
+					Subnet:        "192.168.3.0/24",
+					NextHop:       "0.0.0.0",
+					InterfaceName: getValidIface(),
+				}},
+			}
+			response := ApplyL3Config(httpL3RequestFromConfig(req))
+			Expect(response.Success).To(BeTrue())
+		})
+
+		AfterAll(func() {
+			unsetL3ConfigCache(testTableName)
+			tableID, err := GetTableID(testTableName, req.Subnet, false)
+			Expect(err).NotTo(HaveOccurred())
+			DeleteTable(testTableName, tableID)
+		})
+
+		It("no repair without drift", func() {
+			Expect(HealL3Configs()).To(HaveLen(0))
+		})
+
+		It("restores route and rule", func() {
+			tableID, err := GetTableID(testTableName, req.Subnet, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteRoutes(tableID)).To(Succeed())
+			Expect(deleteRule(tableID)).To(Succeed())
+			reports := HealL3Configs()
+			Expect(reports).To(HaveLen(1))
+			Expect(reports[0].Name).To(Equal(testTableName))
+			Expect(reports[0].Success).To(BeTrue())
+			Expect(reports[0].RuleRestored).To(BeTrue())
+			Expect(reports[0].RouteOutcomes).To(HaveLen(1))
+			Expect(reports[0].RouteOutcomes[0].Action).To(Equal(RouteAdded))
+			Expect(isRuleExist(tableID)).To(BeTrue())
+		})
+
+		It("checks link of L3 config", func() {
+			Expect(isL3ConfigLink(getValidIface())).To(BeTrue())
+			Expect(isL3ConfigLink("not-exist")).To(BeFalse())
+		})
+
+		It("checks table of L3 config", func() {
+			Expect(isL3ConfigTable(MIX_TABLE_INDEX)).To(BeTrue())
+			Expect(isL3ConfigTable(254)).To(BeFalse())
+			Expect(isL3ConfigTable(0)).To(BeFalse())
+		})
 	})
 
 	Context("API", func() {
@@ -208,6 +639,14 @@ func httpL3Request(netName, subnet, dst string, force bool) *http.Request {
 	return req
 }
 
+func httpL3RequestFromConfig(requestL3Config L3ConfigRequest) *http.Request {
+	l3config, err := json.Marshal(requestL3Config)
+	Expect(err).NotTo(HaveOccurred())
+	req, err := http.NewRequest("PUT", "", bytes.NewBuffer(l3config))
+	Expect(err).NotTo(HaveOccurred())
+	return req
+}
+
 func httpRouteRequest(subnet, dst string) *http.Request {
 	route := HostRoute{
 		Subnet:        dst,
@@ -234,3 +673,17 @@ func getValidIface() string {
 	Expect(notFound).To(BeFalse())
 	return ""
 }
+
+type fakeSpeaker struct {
+	announced map[string][]string
+}
+
+func (s *fakeSpeaker) Announce(name string, tableID int, prefixes []string) error {
+	s.announced[name] = prefixes
+	return nil
+}
+
+func (s *fakeSpeaker) Withdraw(name string) error {
+	delete(s.announced, name)
+	return nil
+}
@@ -9,11 +9,11 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"hash/fnv"
 	"log"
 	"net"
 	"os"
-	"sort"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -23,9 +23,15 @@ import (
 const (
 	MIX_TABLE_INDEX       = 100
 	DEFAULT_RT_TABLE_PATH = "/etc/iproute2/rt_tables"
+	// table ID is derived from network name in [MIX_TABLE_INDEX, MIX_TABLE_INDEX+TABLE_ID_RANGE)
+	TABLE_ID_RANGE  = 1 << 16
+	MAX_TABLE_PROBE = 64
+	// RT_TABLE_WRITE_ENV enables writing table names to rt_tables (cosmetic, for ip route show table <name>)
+	RT_TABLE_WRITE_ENV = "RT_TABLE_WRITE"
 )
 
 var RT_TABLE_PATH string = DEFAULT_RT_TABLE_PATH
+var RT_TABLE_WRITE bool = false
 
 func SetRTTablePath() {
 	setTablePath, found := os.LookupEnv("RT_TABLE_PATH")
@@ -34,24 +40,191 @@ func SetRTTablePath() {
 	} else {
 		RT_TABLE_PATH = DEFAULT_RT_TABLE_PATH
 	}
+	RT_TABLE_WRITE, _ = strconv.ParseBool(os.Getenv(RT_TABLE_WRITE_ENV))
+}
+
+// tableState is a snapshot of tables in use, kernel rules and routes are the source of truth
+type tableState struct {
+	// ruleSrcs maps table ID to source subnets of the rules looking up the table
+	ruleSrcs map[int][]string
+	// ownedRoutes maps table ID to number of routes programmed by the daemon (MULTI_NIC_ROUTE_PROTOCOL)
+	ownedRoutes map[int]int
+	// foreignRoutes is a set of table IDs that have routes of other protocols
+	foreignRoutes map[int]bool
+	// names maps table ID to name from rt_tables (read-only, legacy allocation)
+	names   map[int]string
+	nameIDs map[string]int
+}
+
+func getTableState() (*tableState, error) {
+	state := &tableState{
+		ruleSrcs:      make(map[int][]string),
+		ownedRoutes:   make(map[int]int),
+		foreignRoutes: make(map[int]bool),
+		names:         make(map[int]string),
+		nameIDs:       make(map[string]int),
+	}
+	rules, err := netlink.RuleList(netlink.FAMILY_V4)
+	if err != nil {
+		return state, err
+	}
+	for _, rule := range rules {
+		src := ""
+		if rule.Src != nil {
+			src = rule.Src.String()
+		}
+		state.ruleSrcs[rule.Table] = append(state.ruleSrcs[rule.Table], src)
+	}
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: 0}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return state, err
+	}
+	for _, route := range routes {
+		if route.Protocol == MULTI_NIC_ROUTE_PROTOCOL {
+			state.ownedRoutes[route.Table] += 1
+		} else {
+			state.foreignRoutes[route.Table] = true
+		}
+	}
+	state.names, state.nameIDs = readTableNames()
+	return state, nil
+}
+
+// getTableIDCandidates returns table IDs derived from network name in probing order
+func getTableIDCandidates(tableName string) []int {
+	h := fnv.New32a()
+	h.Write([]byte(tableName))
+	start := int(h.Sum32() % TABLE_ID_RANGE)
+	candidates := []int{}
+	for probe := 0; probe < MAX_TABLE_PROBE; probe++ {
+		tableID := MIX_TABLE_INDEX + (start+probe)%TABLE_ID_RANGE
+		if isL3ConfigTable(tableID) {
+			candidates = append(candidates, tableID)
+		}
+	}
+	return candidates
+}
+
+// getSubnetSrc returns subnet in the form of rule source, empty if invalid
+func getSubnetSrc(subnet string) string {
+	if _, src, err := net.ParseCIDR(subnet); err == nil {
+		return src.String()
+	}
+	return ""
+}
+
+// hasSubnetRule checks if the table is looked up by a rule of the subnet
+func (s *tableState) hasSubnetRule(tableID int, subnet string) bool {
+	subnetSrc := getSubnetSrc(subnet)
+	for _, ruleSrc := range s.ruleSrcs[tableID] {
+		if ruleSrc == subnetSrc {
+			return true
+		}
+	}
+	return false
+}
+
+// isClaimedByOther checks if the table is named for another table, has routes not programmed by the daemon,
+// or is looked up by a rule of another subnet; the rule of the previous subnet of the same network
+// is not a claim if the table has routes programmed by the daemon and the subnet is not of another cached network
+func (s *tableState) isClaimedByOther(tableID int, tableName string, subnet string) bool {
+	if name, found := s.names[tableID]; found && name != tableName {
+		return true
+	}
+	if s.foreignRoutes[tableID] {
+		return true
+	}
+	subnetSrc := getSubnetSrc(subnet)
+	for _, ruleSrc := range s.ruleSrcs[tableID] {
+		if ruleSrc == subnetSrc {
+			continue
+		}
+		if s.ownedRoutes[tableID] == 0 || isSubnetOfOtherNetwork(tableName, ruleSrc) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubnetOfOtherNetwork checks if the rule source is the subnet of another cached network or rail
+func isSubnetOfOtherNetwork(tableName string, ruleSrc string) bool {
+	for _, req := range listL3ConfigCache() {
+		if req.Name != tableName && getSubnetSrc(req.Subnet) == ruleSrc {
+			return true
+		}
+		for _, rail := range req.Rails {
+			if GetRailTableName(req.Name, rail.Index) != tableName && getSubnetSrc(rail.Subnet) == ruleSrc {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findTableID returns table ID of network, -1 if not exists
+func (s *tableState) findTableID(tableName string, subnet string) int {
+	if tableID, found := s.nameIDs[tableName]; found {
+		// allocated by name in rt_tables
+		return tableID
+	}
+	for _, tableID := range getTableIDCandidates(tableName) {
+		if s.isClaimedByOther(tableID, tableName, subnet) {
+			continue
+		}
+		if s.hasSubnetRule(tableID, subnet) || s.ownedRoutes[tableID] > 0 {
+			return tableID
+		}
+		return -1
+	}
+	return -1
+}
+
+// replaceRule adds rule of the subnet to the table after deleting rules of previous subnets
+func (s *tableState) replaceRule(subnet string, tableID int) error {
+	for range s.ruleSrcs[tableID] {
+		if err := deleteRule(tableID); err != nil {
+			log.Printf("failed to delete previous rule of table %d: %v", tableID, err)
+		}
+	}
+	return addRule(subnet, tableID)
+}
+
+// allocateTableID returns the first derived table ID not in use by others
+func (s *tableState) allocateTableID(tableName string, subnet string) int {
+	for _, tableID := range getTableIDCandidates(tableName) {
+		if !s.isClaimedByOther(tableID, tableName, subnet) {
+			return tableID
+		}
+	}
+	return -1
+}
+
+// lookupTableID returns table ID of network without any change, -1 if not exists
+func lookupTableID(tableName string, subnet string) (int, error) {
+	state, err := getTableState()
+	if err != nil {
+		return -1, err
+	}
+	return state.findTableID(tableName, subnet), nil
 }
 
 func GetTableID(tableName string, subnet string, addIfNotExists bool) (int, error) {
-	foundID, reservedIDs, err := getTableIDAndReservedIDs(tableName)
+	state, err := getTableState()
 	if err != nil {
-		log.Printf("failed to get table ID %s: %v (%d)", tableName, err, foundID)
-		return foundID, err
+		log.Printf("failed to get table ID %s: %v", tableName, err)
+		return -1, err
 	}
+	foundID := state.findTableID(tableName, subnet)
 	if addIfNotExists && foundID == -1 {
-		foundID, err = addTable(tableName, reservedIDs)
-		if err == nil {
-			// delete existing rule
-			deleteRule(foundID)
-			err = addRule(subnet, foundID)
+		foundID = state.allocateTableID(tableName, subnet)
+		if foundID == -1 {
+			return foundID, errors.New("No available ID")
 		}
+		log.Printf("allocate table %d to %s", foundID, tableName)
+		writeTableName(foundID, tableName)
 	}
-	if foundID != -1 && !isRuleExist(foundID) {
-		err = addRule(subnet, foundID)
+	if foundID != -1 && !state.hasSubnetRule(foundID, subnet) {
+		err = state.replaceRule(subnet, foundID)
 	}
 	return foundID, err
 }
@@ -62,16 +235,7 @@ func DeleteTable(tableName string, tableID int) error {
 		log.Printf("failed to delete routes in table %d: %v", tableID, err)
 		return err
 	}
-	input, err := ioutil.ReadFile(RT_TABLE_PATH)
-	if err != nil {
-		log.Printf("failed to read %s: %v", RT_TABLE_PATH, err)
-		return err
-	}
-	output := strings.Replace(string(input), getTableLine(tableID, tableName), "", 1)
-	err = ioutil.WriteFile(RT_TABLE_PATH, []byte(output), 0666)
-	if err != nil {
-		log.Printf("failed to update %s: %v", RT_TABLE_PATH, err)
-	}
+	removeTableName(tableID, tableName)
 	err = deleteRule(tableID)
 	return err
 }
@@ -103,14 +267,14 @@ func isRuleExist(tableID int) bool {
 	return false
 }
 
-func getTableIDAndReservedIDs(tableName string) (int, []int, error) {
-	foundID := -1
-	reservedIDs := []int{}
+// readTableNames reads table names from rt_tables if exists
+func readTableNames() (map[int]string, map[string]int) {
+	names := make(map[int]string)
+	nameIDs := make(map[string]int)
 
 	file, err := os.Open(RT_TABLE_PATH)
 	if err != nil {
-		log.Printf("Cannot open rt_tables file %s: %v", RT_TABLE_PATH, err)
-		return foundID, reservedIDs, err
+		return names, nameIDs
 	}
 	defer file.Close()
 	scanner := bufio.NewScanner(file)
@@ -127,39 +291,74 @@ func getTableIDAndReservedIDs(tableName string) (int, []int, error) {
 				log.Printf("Cannot parse table ID %s: %v", splited[0], err)
 				continue
 			}
-			if splited[1] == tableName {
-				foundID = int(tableID)
-			} else {
-				if tableID >= MIX_TABLE_INDEX {
-					reservedIDs = append(reservedIDs, int(tableID))
-				}
-			}
+			names[int(tableID)] = splited[1]
+			nameIDs[splited[1]] = int(tableID)
 		}
 	}
-	return foundID, reservedIDs, scanner.Err()
+	return names, nameIDs
 }
 
-func addTable(tableName string, reservedIDs []int) (int, error) {
-	foundID := -1
-	// add table
-	// 1. sort reserved ID
-	sort.Ints(reservedIDs)
-	// 2. find available ID
-	for index, tableID := range reservedIDs {
-		if index+MIX_TABLE_INDEX != tableID {
-			foundID = index + MIX_TABLE_INDEX
-			break
+// writeTableName adds table name to rt_tables if enabled
+func writeTableName(tableID int, tableName string) {
+	updateTableNames(func(content string) string {
+		if strings.Contains(content, getTableLine(tableID, tableName)) {
+			return content
 		}
-	}
-	if foundID != -1 {
-		file, err := os.OpenFile(RT_TABLE_PATH, os.O_APPEND|os.O_WRONLY, 0644)
-		if err == nil {
-			defer file.Close()
-			_, err = file.WriteString(getTableLine(foundID, tableName))
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
 		}
-		return foundID, fmt.Errorf("failed to add table: %v (%s)", err, RT_TABLE_PATH)
+		return content + getTableLine(tableID, tableName)
+	})
+}
+
+// removeTableName removes table name from rt_tables if enabled
+func removeTableName(tableID int, tableName string) {
+	updateTableNames(func(content string) string {
+		return strings.Replace(content, getTableLine(tableID, tableName), "", 1)
+	})
+}
+
+// updateTableNames rewrites rt_tables by atomic rename, failure is only logged
+func updateTableNames(update func(content string) string) {
+	if !RT_TABLE_WRITE {
+		return
+	}
+	input, err := os.ReadFile(RT_TABLE_PATH)
+	if err != nil {
+		log.Printf("failed to read %s: %v", RT_TABLE_PATH, err)
+		return
+	}
+	output := update(string(input))
+	if output == string(input) {
+		return
+	}
+	if err = writeFileAtomic(RT_TABLE_PATH, []byte(output)); err != nil {
+		log.Printf("failed to update %s: %v", RT_TABLE_PATH, err)
+	}
+}
+
+// writeFileAtomic writes a temporary file in the same directory and renames it to the path,
+// the directory (not the file itself) must be mounted for rename
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+	if _, err = tmpFile.Write(data); err == nil {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err != nil {
+		return err
 	}
-	return foundID, errors.New("No available ID")
+	return os.Rename(tmpPath, path)
 }
 
 func deleteRule(tableID int) error {
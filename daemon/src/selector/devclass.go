@@ -11,35 +11,48 @@ import (
 	"github.com/foundation-model-stack/multi-nic-cni/daemon/iface"
 )
 
-type DevClassSelector struct{}
+type DevClassSelector struct {
+	Mode SelectMode
+}
+
+// getDevClassProducts returns a map from vendor to products of device class
+func getDevClassProducts(devClass string) (map[string][]string, error) {
+	devSpecMap := make(map[string][]string)
+	devSpec, err := DeviceClassHandler.Get(devClass)
+	if err != nil {
+		return devSpecMap, err
+	}
+	for _, deviceID := range devSpec.DeviceIDs {
+		devSpecMap[deviceID.Vendor] = deviceID.Products
+	}
+	return devSpecMap, nil
+}
+
+// isDevClassMember checks if vendor and product of interface are in device class
+func isDevClassMember(devSpecMap map[string][]string, info iface.InterfaceInfoType) bool {
+	products, exists := devSpecMap[info.Vendor]
+	if !exists {
+		// not in expected vendor
+		return false
+	}
+	for _, product := range products {
+		if product == info.Product {
+			return true
+		}
+	}
+	// not in expected product
+	return false
+}
 
-func (DevClassSelector) Select(req NICSelectRequest, interfaceNameMap map[string]string, nameNetMap map[string]string, resourceMap map[string][]string) []string {
+func (s DevClassSelector) Select(req NICSelectRequest, interfaceNameMap map[string]string, nameNetMap map[string]string, resourceMap map[string][]string) []string {
 	if req.NicSet.DevClass != "" {
-		devSpec, err := DeviceClassHandler.Get(req.NicSet.DevClass)
+		devSpecMap, err := getDevClassProducts(req.NicSet.DevClass)
 		if err == nil {
-			devSpecMap := make(map[string][]string)
-			for _, deviceID := range devSpec.DeviceIDs {
-				devSpecMap[deviceID.Vendor] = deviceID.Products
-			}
-
 			for _, devName := range interfaceNameMap {
 				if netAddress, exists := nameNetMap[devName]; exists {
 					interfaceMap := iface.GetInterfaceInfoCache()
 					if info, exists := interfaceMap[devName]; exists {
-						if products, exists := devSpecMap[info.Vendor]; exists {
-							found := false
-							for _, product := range products {
-								if product == info.Product {
-									found = true
-									break
-								}
-							}
-							if !found {
-								// not in expected product
-								delete(interfaceNameMap, netAddress)
-							}
-						} else {
-							// not in expected vendor
+						if !isDevClassMember(devSpecMap, info) {
 							delete(interfaceNameMap, netAddress)
 						}
 					}
@@ -50,5 +63,5 @@ func (DevClassSelector) Select(req NICSelectRequest, interfaceNameMap map[string
 		}
 	}
 	log.Printf("no device class")
-	return (DefaultSelector{}).Select(req, interfaceNameMap, nameNetMap, resourceMap)
+	return (DefaultSelector{Mode: s.Mode}).Select(req, interfaceNameMap, nameNetMap, resourceMap)
 }
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package selector
+
+import (
+	"log"
+	"sort"
+
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/backend"
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/iface"
+)
+
+// MaxPluginScore is the highest score of a score plugin before weighting
+const MaxPluginScore = 100
+
+// Candidate is a master network address to be filtered and scored
+type Candidate struct {
+	NetAddress string
+	Master     string
+	Info       iface.InterfaceInfoType
+}
+
+// SelectContext is the request shared by plugins of a selection
+type SelectContext struct {
+	Req         NICSelectRequest
+	ResourceMap map[string][]string
+}
+
+// FilterPlugin removes candidates that cannot be selected
+type FilterPlugin interface {
+	// Filter returns candidates that pass in order and reason of each filtered network address
+	Filter(ctx *SelectContext, candidates []Candidate) ([]Candidate, map[string]string)
+}
+
+// ScorePlugin ranks candidates that pass all filters
+type ScorePlugin interface {
+	// Score returns score from 0 to MaxPluginScore of each candidate network address
+	Score(ctx *SelectContext, candidates []Candidate) map[string]int
+}
+
+var filterPlugins = map[string]FilterPlugin{
+	"devClass": DevClassFilter{},
+	"masters":  MastersFilter{},
+	"health":   HealthFilter{},
+}
+
+var scorePlugins = map[string]ScorePlugin{
+	"numa": NumaScore{},
+	"load": LoadScore{},
+	"cost": CostScore{},
+}
+
+// SelectExplanation explains filtered candidates and scores of selected candidates
+type SelectExplanation struct {
+	Filtered []FilteredCandidate `json:"filtered,omitempty"`
+	Scores   []CandidateScore    `json:"scores,omitempty"`
+}
+
+// FilteredCandidate is a candidate removed by filter plugin
+type FilteredCandidate struct {
+	NetAddress string `json:"net"`
+	Master     string `json:"master"`
+	Plugin     string `json:"plugin"`
+	Reason     string `json:"reason"`
+}
+
+// CandidateScore is weighted total and score of each plugin of candidate
+type CandidateScore struct {
+	NetAddress string         `json:"net"`
+	Master     string         `json:"master"`
+	Scores     map[string]int `json:"scores,omitempty"`
+	Total      int            `json:"total"`
+	Selected   bool           `json:"selected"`
+}
+
+// IsPipelinePolicy checks if policy composes filter or score plugins
+func IsPipelinePolicy(policy backend.AttachmentPolicy) bool {
+	return len(policy.Filters) > 0 || len(policy.Scores) > 0
+}
+
+// getCandidates returns candidates in requested order, otherwise in order of network address
+func getCandidates(req NICSelectRequest, interfaceNameMap map[string]string) []Candidate {
+	interfaceMap := iface.GetInterfaceInfoCache()
+	netAddresses := []string{}
+	addedNetAddresses := make(map[string]bool)
+	for _, netAddress := range req.MasterNetAddrs {
+		if _, found := interfaceNameMap[netAddress]; found && !addedNetAddresses[netAddress] {
+			netAddresses = append(netAddresses, netAddress)
+			addedNetAddresses[netAddress] = true
+		}
+	}
+	restNetAddresses := []string{}
+	for netAddress := range interfaceNameMap {
+		if !addedNetAddresses[netAddress] {
+			restNetAddresses = append(restNetAddresses, netAddress)
+		}
+	}
+	sort.Strings(restNetAddresses)
+	netAddresses = append(netAddresses, restNetAddresses...)
+	candidates := []Candidate{}
+	for _, netAddress := range netAddresses {
+		master := interfaceNameMap[netAddress]
+		candidates = append(candidates, Candidate{NetAddress: netAddress, Master: master, Info: interfaceMap[master]})
+	}
+	return candidates
+}
+
+// PipelineSelect selects network addresses by filter plugins in order
+// and then by the highest weighted sum of score plugins, keeping candidate order on tie
+func PipelineSelect(policy backend.AttachmentPolicy, req NICSelectRequest, interfaceNameMap map[string]string, resourceMap map[string][]string) ([]string, *SelectExplanation) {
+	ctx := &SelectContext{Req: req, ResourceMap: resourceMap}
+	explanation := &SelectExplanation{}
+	candidates := getCandidates(req, interfaceNameMap)
+	for _, name := range policy.Filters {
+		plugin, found := filterPlugins[name]
+		if !found {
+			log.Printf("unknown filter plugin %s, skip", name)
+			continue
+		}
+		var reasons map[string]string
+		filteredMasters := make(map[string]string)
+		for _, candidate := range candidates {
+			filteredMasters[candidate.NetAddress] = candidate.Master
+		}
+		candidates, reasons = plugin.Filter(ctx, candidates)
+		filteredNetAddresses := []string{}
+		for netAddress := range reasons {
+			filteredNetAddresses = append(filteredNetAddresses, netAddress)
+		}
+		sort.Strings(filteredNetAddresses)
+		for _, netAddress := range filteredNetAddresses {
+			explanation.Filtered = append(explanation.Filtered, FilteredCandidate{
+				NetAddress: netAddress,
+				Master:     filteredMasters[netAddress],
+				Plugin:     name,
+				Reason:     reasons[netAddress],
+			})
+		}
+	}
+
+	scores := make([]CandidateScore, len(candidates))
+	for index, candidate := range candidates {
+		scores[index] = CandidateScore{NetAddress: candidate.NetAddress, Master: candidate.Master, Scores: make(map[string]int)}
+	}
+	for _, spec := range policy.Scores {
+		plugin, found := scorePlugins[spec.Name]
+		if !found {
+			log.Printf("unknown score plugin %s, skip", spec.Name)
+			continue
+		}
+		weight := spec.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		pluginScores := plugin.Score(ctx, candidates)
+		for index := range scores {
+			score := pluginScores[scores[index].NetAddress]
+			scores[index].Scores[spec.Name] = score
+			scores[index].Total += weight * score
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Total > scores[j].Total
+	})
+
+	maxSize := req.NicSet.NumOfInterfaces
+	if maxSize <= 0 || maxSize > len(scores) {
+		maxSize = len(scores)
+	}
+	selectedMaster := []string{}
+	for index := range scores {
+		if index < maxSize {
+			scores[index].Selected = true
+			selectedMaster = append(selectedMaster, scores[index].NetAddress)
+		}
+	}
+	explanation.Scores = scores
+	return selectedMaster, explanation
+}
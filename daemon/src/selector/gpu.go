@@ -0,0 +1,117 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package selector
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// GPU_RESOLVER_ENV selects GPU resolver: nvml, sysfs, or auto (default) for nvml if available, otherwise sysfs
+	GPU_RESOLVER_ENV = "GPU_RESOLVER"
+	// GPU_RESOURCE_NAMES_ENV is comma-separated resource names of accelerators such as amd.com/gpu,habana.ai/gaudi
+	GPU_RESOURCE_NAMES_ENV = "GPU_RESOURCE_NAMES"
+)
+
+// GPUResourceNames are resource names of accelerators allocated by device plugins
+var GPUResourceNames = []string{GPUResourceName}
+
+var PciDeviceDir = "/sys/bus/pci/devices"
+
+// GPUResolver maps device IDs of accelerators allocated by device plugin to PCI bus IDs
+type GPUResolver interface {
+	GetBusIDMap() map[string]string
+}
+
+// NVMLResolver maps NVIDIA GPU UUIDs to bus IDs by NVML
+type NVMLResolver struct{}
+
+func (NVMLResolver) GetBusIDMap() map[string]string {
+	return GetGPUIDMap()
+}
+
+// SysfsResolver maps bus IDs, DRM card names, and accel names of display and processing accelerator devices
+// to bus IDs from sysfs, for device plugins that allocate devices by one of them
+type SysfsResolver struct{}
+
+func (SysfsResolver) GetBusIDMap() map[string]string {
+	busIdMap := make(map[string]string)
+	entries, err := os.ReadDir(PciDeviceDir)
+	if err != nil {
+		log.Printf("cannot read %s: %v", PciDeviceDir, err)
+		return busIdMap
+	}
+	for _, entry := range entries {
+		busId := entry.Name()
+		class, err := os.ReadFile(filepath.Join(PciDeviceDir, busId, "class"))
+		if err != nil {
+			continue
+		}
+		// 0x03: display controller, 0x12: processing accelerator
+		classStr := strings.TrimSpace(string(class))
+		if !strings.HasPrefix(classStr, "0x03") && !strings.HasPrefix(classStr, "0x12") {
+			continue
+		}
+		busIdMap[busId] = busId
+		for _, subDir := range []string{"drm", "accel"} {
+			names, err := os.ReadDir(filepath.Join(PciDeviceDir, busId, subDir))
+			if err != nil {
+				continue
+			}
+			for _, name := range names {
+				busIdMap[name.Name()] = busId
+			}
+		}
+	}
+	log.Printf("SysfsResolver: %v\n", busIdMap)
+	return busIdMap
+}
+
+// GetGPUResolver returns GPU resolver by name, NVML if available or sysfs for auto or unknown name
+func GetGPUResolver(name string) GPUResolver {
+	switch name {
+	case "nvml":
+		return NVMLResolver{}
+	case "sysfs":
+		return SysfsResolver{}
+	}
+	if name != "" && name != "auto" {
+		log.Printf("unknown GPU resolver %s, use auto", name)
+	}
+	if busIdMap := GetGPUIDMap(); len(busIdMap) > 0 {
+		return NVMLResolver{}
+	}
+	return SysfsResolver{}
+}
+
+// InitGPUResolver sets GPU resource names and bus ID map from GPU_RESOURCE_NAMES and GPU_RESOLVER
+// and reinitializes NumaAwareSelectorInstance
+func InitGPUResolver() {
+	if resourceNames, found := os.LookupEnv(GPU_RESOURCE_NAMES_ENV); found && resourceNames != "" {
+		GPUResourceNames = []string{}
+		for _, resourceName := range strings.Split(resourceNames, ",") {
+			if resourceName = strings.TrimSpace(resourceName); resourceName != "" {
+				GPUResourceNames = append(GPUResourceNames, resourceName)
+			}
+		}
+	}
+	resolver := GetGPUResolver(os.Getenv(GPU_RESOLVER_ENV))
+	GPUIdBusIdMap = resolver.GetBusIDMap()
+	log.Printf("GPU resource names %v with %d device(s) from %T", GPUResourceNames, len(GPUIdBusIdMap), resolver)
+	NumaAwareSelectorInstance = InitNumaAwareSelector(TopologyFilePath, GPUIdBusIdMap)
+}
+
+// getGPUIDs returns device IDs of all GPU resource names in the resource map
+func getGPUIDs(resourceMap map[string][]string) []string {
+	gpuIds := []string{}
+	for _, resourceName := range GPUResourceNames {
+		gpuIds = append(gpuIds, resourceMap[resourceName]...)
+	}
+	return gpuIds
+}
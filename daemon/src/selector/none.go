@@ -13,7 +13,19 @@ import (
 	gnet "github.com/jaypipes/ghw/pkg/net"
 )
 
-type DefaultSelector struct{}
+// SelectMode is how DefaultSelector picks interfaces if fewer than candidates are requested
+type SelectMode string
+
+const (
+	// Ordered picks the first interfaces in order of network address
+	Ordered SelectMode = "ordered"
+	// LeastAttached picks interfaces with the fewest pods attached to the network on the host
+	LeastAttached SelectMode = "leastAttached"
+)
+
+type DefaultSelector struct {
+	Mode SelectMode
+}
 
 func getMasterNames(ifaceNameMap map[string]string, req NICSelectRequest) []string {
 	masters := []string{}
@@ -45,8 +57,8 @@ func getDeviceIDs(deviceMap map[string]*gnet.NIC, req NICSelectRequest) []string
 	return deviceIDs
 }
 
-// DefaultSelector simply selects interface in order
-func (DefaultSelector) Select(req NICSelectRequest, interfaceNameMap map[string]string, nameNetMap map[string]string, resourceMap map[string][]string) []string {
+// DefaultSelector simply selects interface in order, or by the fewest attached pods in LeastAttached mode
+func (s DefaultSelector) Select(req NICSelectRequest, interfaceNameMap map[string]string, nameNetMap map[string]string, resourceMap map[string][]string) []string {
 	selectedMaster := []string{}
 	maxSize := req.NicSet.NumOfInterfaces
 	fixedSet := req.NicSet.InterfaceNames
@@ -74,8 +86,21 @@ func (DefaultSelector) Select(req NICSelectRequest, interfaceNameMap map[string]
 	}
 	if maxSize > 0 {
 		maxSize = int(math.Min(float64(len(selectedMaster)), float64(maxSize)))
+		if s.Mode == LeastAttached && len(fixedSet) == 0 && maxSize < len(selectedMaster) {
+			selectedMaster = SortByAttachedCount(selectedMaster, interfaceNameMap, getAttachedCount(req.HostName, req.NetAttachDefName))
+		}
 	} else {
 		maxSize = len(selectedMaster)
 	}
 	return selectedMaster[0:maxSize]
 }
+
+// SortByAttachedCount sorts network addresses by attached count of their interfaces, keeping order on tie
+func SortByAttachedCount(selectedMaster []string, interfaceNameMap map[string]string, attachedCount map[string]int) []string {
+	sorted := append([]string{}, selectedMaster...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return attachedCount[interfaceNameMap[sorted[i]]] < attachedCount[interfaceNameMap[sorted[j]]]
+	})
+	log.Printf("sorted by attached count %v: %v", attachedCount, sorted)
+	return sorted
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2022- IBM Inc. All rights reserved
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package selector
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/backend"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var IPPoolHandler *backend.IPPoolHandler
+
+// DevClassFilter keeps interfaces in device class of the request, all if not requested
+type DevClassFilter struct{}
+
+func (DevClassFilter) Filter(ctx *SelectContext, candidates []Candidate) ([]Candidate, map[string]string) {
+	reasons := make(map[string]string)
+	devClass := ctx.Req.NicSet.DevClass
+	if devClass == "" {
+		return candidates, reasons
+	}
+	devSpecMap, err := getDevClassProducts(devClass)
+	if err != nil {
+		log.Printf("cannot get device class %s: %v", devClass, err)
+		return candidates, reasons
+	}
+	passed := []Candidate{}
+	for _, candidate := range candidates {
+		if !isDevClassMember(devSpecMap, candidate.Info) {
+			reasons[candidate.NetAddress] = fmt.Sprintf("%s:%s not in device class %s", candidate.Info.Vendor, candidate.Info.Product, devClass)
+			continue
+		}
+		passed = append(passed, candidate)
+	}
+	return passed, reasons
+}
+
+// MastersFilter keeps interfaces requested by names in their order, otherwise by master network addresses
+type MastersFilter struct{}
+
+func (MastersFilter) Filter(ctx *SelectContext, candidates []Candidate) ([]Candidate, map[string]string) {
+	reasons := make(map[string]string)
+	if len(ctx.Req.NicSet.InterfaceNames) > 0 {
+		candidateMap := make(map[string]Candidate)
+		for _, candidate := range candidates {
+			candidateMap[candidate.Master] = candidate
+		}
+		passed := []Candidate{}
+		for _, master := range ctx.Req.NicSet.InterfaceNames {
+			if candidate, found := candidateMap[master]; found {
+				passed = append(passed, candidate)
+				delete(candidateMap, master)
+			}
+		}
+		for _, candidate := range candidateMap {
+			reasons[candidate.NetAddress] = fmt.Sprintf("%s not in requested masters", candidate.Master)
+		}
+		return passed, reasons
+	}
+	if len(ctx.Req.MasterNetAddrs) > 0 {
+		requested := make(map[string]bool)
+		for _, netAddress := range ctx.Req.MasterNetAddrs {
+			requested[netAddress] = true
+		}
+		passed := []Candidate{}
+		for _, candidate := range candidates {
+			if !requested[candidate.NetAddress] {
+				reasons[candidate.NetAddress] = fmt.Sprintf("%s not in requested master networks", candidate.NetAddress)
+				continue
+			}
+			passed = append(passed, candidate)
+		}
+		return passed, reasons
+	}
+	return candidates, reasons
+}
+
+// HealthFilter removes interfaces whose link is down or has no carrier
+type HealthFilter struct{}
+
+func (HealthFilter) Filter(ctx *SelectContext, candidates []Candidate) ([]Candidate, map[string]string) {
+	reasons := make(map[string]string)
+	passed := []Candidate{}
+	for _, candidate := range candidates {
+		// state is empty if not reported
+		switch state := candidate.Info.OperState; {
+		case state == "down" || state == "lowerlayerdown" || state == "notpresent":
+			reasons[candidate.NetAddress] = fmt.Sprintf("link %s", state)
+		case state != "" && !candidate.Info.Carrier:
+			reasons[candidate.NetAddress] = "no carrier"
+		default:
+			passed = append(passed, candidate)
+		}
+	}
+	return passed, reasons
+}
+
+// NumaScore scores interface by share of GPUs of the pod on its NUMA node
+type NumaScore struct{}
+
+func (NumaScore) Score(ctx *SelectContext, candidates []Candidate) map[string]int {
+	scores := make(map[string]int)
+	s := NumaAwareSelectorInstance
+	gpuIds := getGPUIDs(ctx.ResourceMap)
+	if len(gpuIds) == 0 || s == nil {
+		return scores
+	}
+	numaCount := make(map[string]int)
+	for _, gpuId := range gpuIds {
+		numaCount[s.NumaMap[s.gpuIDBusMap[gpuId]]]++
+	}
+	for _, candidate := range candidates {
+		numaId, found := s.NumaMap[candidate.Info.PciAddress]
+		if !found {
+			numaId = candidate.Info.NumaNode
+		}
+		if numaId == "" {
+			continue
+		}
+		scores[candidate.NetAddress] = MaxPluginScore * numaCount[numaId] / len(gpuIds)
+	}
+	return scores
+}
+
+// LoadScore scores interface with fewer pods attached to the network on the host higher
+type LoadScore struct{}
+
+func (LoadScore) Score(ctx *SelectContext, candidates []Candidate) map[string]int {
+	scores := make(map[string]int)
+	attachedCount := getAttachedCount(ctx.Req.HostName, ctx.Req.NetAttachDefName)
+	maxCount := 0
+	for _, candidate := range candidates {
+		if attachedCount[candidate.Master] > maxCount {
+			maxCount = attachedCount[candidate.Master]
+		}
+	}
+	for _, candidate := range candidates {
+		if maxCount == 0 {
+			scores[candidate.NetAddress] = MaxPluginScore
+			continue
+		}
+		scores[candidate.NetAddress] = MaxPluginScore * (maxCount - attachedCount[candidate.Master]) / maxCount
+	}
+	return scores
+}
+
+// getAttachedCount returns a map from interface name to number of allocations of the network on the host
+func getAttachedCount(hostName string, defName string) map[string]int {
+	attachedCount := make(map[string]int)
+	if IPPoolHandler == nil {
+		return attachedCount
+	}
+	labelMap := map[string]string{"hostname": hostName, "netname": defName}
+	listOptions := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labelMap).String(),
+	}
+	ippoolSpecMap, err := IPPoolHandler.ListIPPool(listOptions)
+	if err != nil {
+		log.Printf("cannot list IPPool of %s on %s: %v", defName, hostName, err)
+		return attachedCount
+	}
+	for _, ippool := range ippoolSpecMap {
+		attachedCount[ippool.InterfaceName] += len(ippool.Allocations)
+	}
+	return attachedCount
+}
+
+// CostScore scores interface with lower link speed higher to keep faster interfaces for other pods,
+// interface with unknown speed is scored as the slowest
+type CostScore struct{}
+
+func (CostScore) Score(ctx *SelectContext, candidates []Candidate) map[string]int {
+	scores := make(map[string]int)
+	maxSpeed := 0
+	for _, candidate := range candidates {
+		if candidate.Info.Speed > maxSpeed {
+			maxSpeed = candidate.Info.Speed
+		}
+	}
+	for _, candidate := range candidates {
+		if maxSpeed == 0 || candidate.Info.Speed == 0 {
+			scores[candidate.NetAddress] = MaxPluginScore
+			continue
+		}
+		scores[candidate.NetAddress] = MaxPluginScore * (maxSpeed - candidate.Info.Speed) / maxSpeed
+	}
+	return scores
+}
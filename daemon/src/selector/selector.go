@@ -6,10 +6,12 @@
 package selector
 
 import (
+	"github.com/foundation-model-stack/multi-nic-cni/daemon/apierror"
 	"github.com/foundation-model-stack/multi-nic-cni/daemon/backend"
 	"github.com/foundation-model-stack/multi-nic-cni/daemon/iface"
 
 	"context"
+	"fmt"
 	"log"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,9 +37,13 @@ type NicArgs struct {
 	DevClass        string   `json:"class,omitempty"`
 }
 
+// NICSelectResponse returns selected masters with their network addresses in the same order
 type NICSelectResponse struct {
-	DeviceIDs []string `json:"deviceIDs"`
-	Masters   []string `json:"masters"`
+	DeviceIDs      []string `json:"deviceIDs"`
+	Masters        []string `json:"masters"`
+	MasterNetAddrs []string `json:"masterNets,omitempty"`
+	// Explanation is set if network composes filter or score plugins
+	Explanation *SelectExplanation `json:"explanation,omitempty"`
 }
 
 type Selector interface {
@@ -49,7 +55,9 @@ var MultinicnetHandler *backend.MultiNicNetworkHandler
 var NetAttachDefHandler *backend.NetAttachDefHandler
 var K8sClientset *kubernetes.Clientset
 var DeviceClassHandler *backend.DeviceClassHandler
-var GPUIdBusIdMap = GetGPUIDMap()
+
+// GPUIdBusIdMap maps GPU device IDs to PCI bus IDs, set by InitGPUResolver
+var GPUIdBusIdMap = map[string]string{}
 var TopologyFilePath = defaultTopologyFilePath
 var NumaAwareSelectorInstance = InitNumaAwareSelector(TopologyFilePath, GPUIdBusIdMap)
 
@@ -76,12 +84,30 @@ func getDefaultResponse(req NICSelectRequest, masterNameMap map[string]string, n
 			log.Printf("device %s not exists, skip", master)
 		}
 	}
+	return newSelectResponse([]string{}, selectedMasters, nameNetMap)
+}
+
+// newSelectResponse returns select response with network address of each master
+func newSelectResponse(deviceIDs []string, masters []string, nameNetMap map[string]string) NICSelectResponse {
+	masterNetAddrs := []string{}
+	for _, master := range masters {
+		masterNetAddrs = append(masterNetAddrs, nameNetMap[master])
+	}
 	return NICSelectResponse{
-		DeviceIDs: []string{},
-		Masters:   selectedMasters,
+		DeviceIDs:      deviceIDs,
+		Masters:        masters,
+		MasterNetAddrs: masterNetAddrs,
 	}
 }
 
+// getRequestedItems returns requested interface names or network addresses
+func getRequestedItems(req NICSelectRequest) []string {
+	if len(req.NicSet.InterfaceNames) > 0 {
+		return req.NicSet.InterfaceNames
+	}
+	return req.MasterNetAddrs
+}
+
 func InitCache(cfg *rest.Config, hostName string) {
 	hostInterfaceHandler := backend.NewHostInterfaceHandler(cfg, hostName)
 	infos, err := hostInterfaceHandler.GetHostInterfaces()
@@ -92,12 +118,18 @@ func InitCache(cfg *rest.Config, hostName string) {
 			if info.PciAddress != "" {
 				iface.SetDeviceMapCache(info.PciAddress, info.InterfaceName)
 			}
+			for _, slave := range info.Slaves {
+				// device of slave is attached via its bond or team master
+				if slave.PciAddress != "" {
+					iface.SetDeviceMapCache(slave.PciAddress, info.InterfaceName)
+				}
+			}
 		}
 		log.Printf("set %d devices cache from hostinterface CR", iface.GetDeviceMapSize())
 	}
 }
 
-func Select(req NICSelectRequest) NICSelectResponse {
+func Select(req NICSelectRequest) (NICSelectResponse, error) {
 	resourceMap := make(map[string][]string)
 	podDeviceIDs := []string{}
 	podMasters := []string{}
@@ -128,10 +160,7 @@ func Select(req NICSelectRequest) NICSelectResponse {
 	}
 	if len(podMasters) > 0 {
 		// no need of selection returns device IDs with corresponding names
-		return NICSelectResponse{
-			DeviceIDs: podDeviceIDs,
-			Masters:   podMasters,
-		}
+		return newSelectResponse(podDeviceIDs, podMasters, iface.GetNameNetMap()), nil
 	}
 
 	masterNameMap := iface.GetInterfaceNameMap()
@@ -148,7 +177,12 @@ func Select(req NICSelectRequest) NICSelectResponse {
 			}
 		}
 		log.Printf("default master name map: %v\n", defaultMasterNameMap)
-		return getDefaultResponse(req, defaultMasterNameMap, nameNetMap, resourceMap)
+		resp := getDefaultResponse(req, defaultMasterNameMap, nameNetMap, resourceMap)
+		if len(resp.Masters) == 0 {
+			return resp, apierror.New(apierror.NetworkNotFound, req.NetAttachDefName, req.HostName,
+				fmt.Sprintf("failed to get network spec and no default interface available: %v", err)).WithMissing(getRequestedItems(req))
+		}
+		return resp, nil
 	}
 	policy := netSpec.Policy
 
@@ -159,38 +193,57 @@ func Select(req NICSelectRequest) NICSelectResponse {
 		}
 	}
 
-	var selector Selector
+	var selectedMasterNetAddrs []string
+	var explanation *SelectExplanation
 	strategy := Strategy(policy.Strategy)
-	switch strategy {
-	case None:
-		selector = DefaultSelector{}
-	case CostOpt:
-		selector = CostOptSelector{}
-	case PerfOpt:
-		selector = PerfOptSelector{}
-	case DevClass:
-		selector = DevClassSelector{}
-	case Topology:
-		selector = NumaAwareSelectorInstance.GetCopy()
-	default:
-		selector = DefaultSelector{}
+	if IsPipelinePolicy(policy) {
+		selectedMasterNetAddrs, explanation = PipelineSelect(policy, req, filteredMasterNameMap, resourceMap)
+	} else {
+		var selector Selector
+		mode := SelectMode(policy.Mode)
+		switch strategy {
+		case None:
+			selector = DefaultSelector{Mode: mode}
+		case CostOpt:
+			selector = CostOptSelector{}
+		case PerfOpt:
+			selector = PerfOptSelector{}
+		case DevClass:
+			selector = DevClassSelector{Mode: mode}
+		case Topology:
+			selector = NumaAwareSelectorInstance.GetCopy()
+		default:
+			selector = DefaultSelector{Mode: mode}
+		}
+		selectedMasterNetAddrs = selector.Select(req, filteredMasterNameMap, nameNetMap, resourceMap)
 	}
-	selectedMasterNetAddrs := selector.Select(req, filteredMasterNameMap, nameNetMap, resourceMap)
 	selectedMasters := []string{}
 	log.Printf("masterNets %v, %v, %v\n", selectedMasterNetAddrs, filteredMasterNameMap, nameNetMap)
+	selectedMasterMap := make(map[string]bool)
 	for _, netAddress := range selectedMasterNetAddrs {
 		if master, ok := filteredMasterNameMap[netAddress]; ok && master != "" {
+			if selectedMasterMap[master] {
+				// interface with multiple addresses is selected once
+				log.Printf("device %s already selected, skip %s", master, netAddress)
+				continue
+			}
 			log.Printf("select device %s\n", master)
 			if iface.DeviceExists(master) {
 				selectedMasters = append(selectedMasters, master)
+				selectedMasterMap[master] = true
+				// respond the selected network address of interface with multiple addresses
+				nameNetMap[master] = netAddress
 			} else {
 				log.Printf("device %s not exists, skip", master)
 			}
 		}
 	}
 
-	return NICSelectResponse{
-		DeviceIDs: []string{},
-		Masters:   selectedMasters,
+	resp := newSelectResponse([]string{}, selectedMasters, nameNetMap)
+	resp.Explanation = explanation
+	if len(selectedMasters) == 0 {
+		return resp, apierror.New(apierror.NoInterfaceSelected, req.NetAttachDefName, req.HostName,
+			fmt.Sprintf("no interface selected from %d candidate(s) with strategy %q", len(filteredMasterNameMap), strategy)).WithMissing(getRequestedItems(req))
 	}
+	return resp, nil
 }
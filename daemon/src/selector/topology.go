@@ -52,6 +52,8 @@ type NumaAwareSelector struct {
 	NcclTopolgy
 	gpuIDBusMap map[string]string
 	NumaMap     map[string]string
+	// PciPathMap maps PCI bus ID to its NUMA node and upstream bridges of topology
+	PciPathMap map[string][]string
 }
 
 func InitNumaAwareSelector(topologyFilePath string, gpuIdBusIdMap map[string]string) *NumaAwareSelector {
@@ -71,6 +73,7 @@ func InitNumaAwareSelector(topologyFilePath string, gpuIdBusIdMap map[string]str
 		NcclTopolgy: topology,
 		gpuIDBusMap: gpuIdBusIdMap,
 		NumaMap:     numaMap,
+		PciPathMap:  getPciPathMap(topology),
 	}
 }
 
@@ -113,6 +116,107 @@ func getNumaMap(topology NcclTopolgy) map[string]string {
 	return numaMap
 }
 
+// getPciPathMap returns a map from PCI bus ID to path of NUMA node and bus IDs of upstream bridges
+func getPciPathMap(topology NcclTopolgy) map[string][]string {
+	pciPathMap := make(map[string][]string)
+	var addPaths func(pcis []PCITag, path []string)
+	addPaths = func(pcis []PCITag, path []string) {
+		for _, pci := range pcis {
+			busId := normalizeBusID(pci.BusId)
+			pciPathMap[busId] = path
+			if len(pci.PCIs) > 0 {
+				addPaths(pci.PCIs, append(append([]string{}, path...), busId))
+			}
+		}
+	}
+	for _, cpu := range topology.CPUs {
+		addPaths(cpu.PCIs, []string{"numa" + cpu.NumaId})
+	}
+	return pciPathMap
+}
+
+// normalizeBusID returns bus ID in lower case with 4-digit domain as in sysfs,
+// NVML returns 8-digit domain such as 00000000:0C:05.0
+func normalizeBusID(busId string) string {
+	busId = strings.ToLower(busId)
+	if domain, rest, found := strings.Cut(busId, ":"); found && len(domain) > 4 {
+		return domain[len(domain)-4:] + ":" + rest
+	}
+	return busId
+}
+
+// getCommonPathLength returns length of common upstream path,
+// the longer the path the closer the devices, e.g., 1 for the same NUMA node, 2 for the same PCIe switch
+func getCommonPathLength(path []string, cmpPath []string) int {
+	length := 0
+	for length < len(path) && length < len(cmpPath) && path[length] == cmpPath[length] {
+		length++
+	}
+	return length
+}
+
+// SortByPCIeSwitch pairs each GPU of the pod with the closest NIC in topology, preferring NIC behind the same PCIe switch,
+// and returns paired NICs in GPU order followed by the rest. It returns false if GPUs or NICs are not in topology.
+func (s *NumaAwareSelector) SortByPCIeSwitch(selectedMaster []string, interfaceNameMap map[string]string, resourceMap map[string][]string) ([]string, bool) {
+	gpuIds := getGPUIDs(resourceMap)
+	if len(gpuIds) == 0 || len(s.PciPathMap) == 0 {
+		return selectedMaster, false
+	}
+	interfaceMap := iface.GetInterfaceInfoCache()
+	nicPaths := make(map[string][]string)
+	for _, masterNetAddr := range selectedMaster {
+		if info, ok := interfaceMap[interfaceNameMap[masterNetAddr]]; ok {
+			if path, ok := s.PciPathMap[normalizeBusID(info.PciAddress)]; ok {
+				nicPaths[masterNetAddr] = path
+			}
+		}
+	}
+	if len(nicPaths) == 0 {
+		log.Printf("cannot pair by PCIe switch: no NIC in topology")
+		return selectedMaster, false
+	}
+	sortedMaster := []string{}
+	paired := make(map[string]bool)
+	pairedGPU := false
+	for _, gpuId := range gpuIds {
+		gpuPath, ok := s.PciPathMap[normalizeBusID(s.gpuIDBusMap[gpuId])]
+		if !ok {
+			continue
+		}
+		pairedGPU = true
+		bestMaster := ""
+		bestLength := 0
+		for _, masterNetAddr := range selectedMaster {
+			nicPath, ok := nicPaths[masterNetAddr]
+			if !ok {
+				continue
+			}
+			length := getCommonPathLength(gpuPath, nicPath)
+			// prefer NIC not paired yet among the closest
+			if length > bestLength || (length == bestLength && paired[bestMaster] && !paired[masterNetAddr]) {
+				bestMaster = masterNetAddr
+				bestLength = length
+			}
+		}
+		// GPUs share the closest NIC if it is already paired
+		if bestMaster != "" && !paired[bestMaster] {
+			log.Printf("pair GPU %s with %s (common path length %d)", gpuId, interfaceNameMap[bestMaster], bestLength)
+			sortedMaster = append(sortedMaster, bestMaster)
+			paired[bestMaster] = true
+		}
+	}
+	if !pairedGPU {
+		log.Printf("cannot pair by PCIe switch: no GPU in topology")
+		return selectedMaster, false
+	}
+	for _, masterNetAddr := range selectedMaster {
+		if !paired[masterNetAddr] {
+			sortedMaster = append(sortedMaster, masterNetAddr)
+		}
+	}
+	return sortedMaster, true
+}
+
 // getNumaMapFromSysfs get map from pci_address from /sys/devices/pci*/<pci_address>/numa_node
 func getNumaMapFromSysfs() (numaMap map[string]string) {
 	numaMap = make(map[string]string)
@@ -158,7 +262,13 @@ func (s *NumaAwareSelector) Select(req NICSelectRequest, interfaceNameMap map[st
 			selectedMaster = append(selectedMaster, netAddress)
 		}
 	}
-	if maxSize > 0 && maxSize < len(selectedMaster) {
+	if sortedSelectedMaster, ok := s.SortByPCIeSwitch(selectedMaster, interfaceNameMap, resourceMap); ok {
+		// NICs paired with GPUs come first in GPU order
+		selectedMaster = sortedSelectedMaster
+		if maxSize <= 0 || maxSize > len(selectedMaster) {
+			maxSize = len(selectedMaster)
+		}
+	} else if maxSize > 0 && maxSize < len(selectedMaster) {
 		maxSize = int(math.Min(float64(len(selectedMaster)), float64(maxSize)))
 		// must be sorted and selected
 		sortedSelectedMaster := s.SortByNumaAware(selectedMaster, interfaceNameMap, resourceMap)
@@ -174,9 +284,9 @@ func (s *NumaAwareSelector) Select(req NICSelectRequest, interfaceNameMap map[st
 }
 
 func (s *NumaAwareSelector) SortByNumaAware(selectedMaster []string, interfaceNameMap map[string]string, resourceMap map[string][]string) []string {
-	if gpuIds, ok := resourceMap[GPUResourceName]; !ok || len(s.NumaMap) == 0 {
+	if gpuIds := getGPUIDs(resourceMap); len(gpuIds) == 0 || len(s.NumaMap) == 0 {
 		// cannot sort value
-		log.Printf("cannot sort by numa node: resourceMap=%v, NumaMap length=%d", ok, len(s.NumaMap))
+		log.Printf("cannot sort by numa node: GPUs=%d, NumaMap length=%d", len(gpuIds), len(s.NumaMap))
 		return selectedMaster
 	} else {
 		numaPriority := make(map[string]int)
@@ -216,5 +326,6 @@ func (s *NumaAwareSelector) GetCopy() *NumaAwareSelector {
 		NcclTopolgy: s.NcclTopolgy,
 		gpuIDBusMap: s.gpuIDBusMap,
 		NumaMap:     s.NumaMap,
+		PciPathMap:  s.PciPathMap,
 	}
 }
@@ -17,6 +17,11 @@ const (
 	IPVLAN_TYPE = "ipvlan"
 )
 
+var (
+	ipvlanModes = []string{"l2", "l3", "l3s"}
+	ipvlanFlags = []string{"bridge", "private", "vepa"}
+)
+
 type IPVLANPlugin struct {
 }
 
@@ -25,6 +30,9 @@ type IPVLANTypeNetConf struct {
 	Master string `json:"master"`
 	Mode   string `json:"mode"`
 	MTU    int    `json:"mtu"`
+	// Flag and Sysctls are set on each pod interface by multi-nic
+	Flag    string            `json:"flag,omitempty"`
+	Sysctls map[string]string `json:"sysctls,omitempty"`
 }
 
 func (p *IPVLANPlugin) Init(config *rest.Config) error {
@@ -43,6 +51,17 @@ func (p *IPVLANPlugin) GetConfig(net multinicv1.MultiNicNetwork, hifList map[str
 	if err == nil {
 		conf.MTU = val
 	}
+	if err := validateLinkSpec(spec, ipvlanModes, ipvlanFlags); err != nil {
+		return "", make(map[string]string), err
+	}
+	if spec.Mode != "" {
+		conf.Mode = spec.Mode
+	}
+	if spec.MTU != 0 {
+		conf.MTU = spec.MTU
+	}
+	conf.Flag = spec.Flag
+	conf.Sysctls = spec.Sysctls
 	confBytes, err := json.Marshal(conf)
 	if err != nil {
 		return "", make(map[string]string), err
@@ -17,6 +17,11 @@ const (
 	MACVLAN_TYPE = "macvlan"
 )
 
+var (
+	macvlanModes = []string{"bridge", "private", "vepa", "passthru"}
+	macvlanFlags = []string{}
+)
+
 type MACVLANPlugin struct {
 }
 
@@ -25,6 +30,8 @@ type MACVLANTypeNetConf struct {
 	Master string `json:"master"` // Name of the master interfce (e.g., eth0)
 	Mode   string `json:"mode"`   // Mod of the macvlan interface (e.g., bridge, private)
 	MTU    int    `json:"mtu"`
+	// Sysctls set on each pod interface by multi-nic
+	Sysctls map[string]string `json:"sysctls,omitempty"`
 }
 
 func (p *MACVLANPlugin) Init(config *rest.Config) error {
@@ -43,6 +50,16 @@ func (p *MACVLANPlugin) GetConfig(net multinicv1.MultiNicNetwork, hifList map[st
 	if err == nil {
 		conf.MTU = val
 	}
+	if err := validateLinkSpec(spec, macvlanModes, macvlanFlags); err != nil {
+		return "", make(map[string]string), err
+	}
+	if spec.Mode != "" {
+		conf.Mode = spec.Mode
+	}
+	if spec.MTU != 0 {
+		conf.MTU = spec.MTU
+	}
+	conf.Sysctls = spec.Sysctls
 	confBytes, _ := json.Marshal(conf)
 	return string(confBytes), make(map[string]string), nil
 }
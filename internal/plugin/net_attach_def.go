@@ -79,6 +79,7 @@ type NetConf struct {
 	IsMultiNICIPAM bool        `json:"multiNICIPAM,omitempty"`
 	DaemonIP       string      `json:"daemonIP"`
 	DaemonPort     int         `json:"daemonPort"`
+	IfNameTemplate string      `json:"ifNameTemplate,omitempty"`
 }
 
 type NetworkStatus struct {
@@ -225,6 +226,7 @@ func NetToDef(namespace string, net *multinicv1.MultiNicNetwork, pluginStr strin
 		MasterNetAddrs: net.Spec.MasterNetAddrs,
 		IsMultiNICIPAM: net.Spec.IsMultiNICIPAM,
 		DaemonPort:     vars.DaemonPort,
+		IfNameTemplate: net.Spec.IfNameTemplate,
 	}
 	var ipamObj map[string]interface{}
 	configBytes, _ := json.Marshal(config)
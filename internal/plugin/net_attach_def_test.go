@@ -149,6 +149,14 @@ var _ = Describe("NetAttachDef test", func() {
 			Expect(multinicnetwork.ObjectMeta.Finalizers).To(BeEmpty())
 		})
 
+		It("NetToDef with ifNameTemplate", func() {
+			templateNet := multinicnetwork.DeepCopy()
+			templateNet.Spec.IfNameTemplate = "rail{netIndex}"
+			def, err := NetToDef("default", templateNet, mainPlugin, annotations)
+			Expect(err).To(BeNil())
+			Expect(def.Spec.Config).To(ContainSubstring(`"ifNameTemplate":"rail{netIndex}"`))
+		})
+
 		Context("CheckDefChanged", func() {
 			type testCase struct {
 				description string
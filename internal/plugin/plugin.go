@@ -18,6 +18,9 @@ const (
 
 var netConfKeys []string = []string{"cniVersion", "type"}
 
+// typedConfKeys are set from typed fields of PluginSpec, kept if not empty
+var typedConfKeys []string = []string{"mode", "mtu", "flag", "sysctls"}
+
 type Plugin interface {
 	GetConfig(net multinicv1.MultiNicNetwork, hifList map[string]multinicv1.HostInterface) (string, map[string]string, error)
 	CleanUp(net multinicv1.MultiNicNetwork) error
@@ -47,6 +50,12 @@ func RemoveEmpty(args map[string]string, pluginStr string) string {
 		cleanedObj[key] = pluginObj[key]
 	}
 
+	for _, key := range typedConfKeys {
+		if value, exist := pluginObj[key]; exist && !isEmptyValue(value) {
+			cleanedObj[key] = value
+		}
+	}
+
 	for key, value := range pluginObj {
 		if _, exist := args[key]; exist {
 			cleanedObj[key] = value
@@ -55,3 +64,17 @@ func RemoveEmpty(args map[string]string, pluginStr string) string {
 	cleanedBytes, _ := json.Marshal(cleanedObj)
 	return string(cleanedBytes)
 }
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -112,6 +112,43 @@ var _ = Describe("Test GetConfig of main plugins", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("ipvlan main plugin with typed fields", func() {
+		ipvlanPlugin := &IPVLANPlugin{}
+		cniArgs := map[string]string{"mode": "l2", "mtu": "1500"}
+		multinicnetwork := getMultiNicCNINetwork("test-ipvlan-typed", cniVersion, IPVLAN_TYPE, cniArgs)
+		multinicnetwork.Spec.MainPlugin.Mode = "l3s"
+		multinicnetwork.Spec.MainPlugin.MTU = 9000
+		multinicnetwork.Spec.MainPlugin.Flag = "private"
+		multinicnetwork.Spec.MainPlugin.Sysctls = map[string]string{"net.ipv4.conf.IFNAME.arp_ignore": "1"}
+
+		mainPlugin, _, err := ipvlanPlugin.GetConfig(*multinicnetwork, nil)
+		Expect(err).NotTo(HaveOccurred())
+		conf := &IPVLANTypeNetConf{}
+		err = json.Unmarshal([]byte(RemoveEmpty(cniArgs, mainPlugin)), conf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf.Mode).To(Equal("l3s"))
+		Expect(conf.MTU).To(Equal(9000))
+		Expect(conf.Flag).To(Equal("private"))
+		Expect(conf.Sysctls).To(HaveKeyWithValue("net.ipv4.conf.IFNAME.arp_ignore", "1"))
+	})
+
+	It("invalid typed fields", func() {
+		ipvlanPlugin := &IPVLANPlugin{}
+		macvlanPlugin := &MACVLANPlugin{}
+		multinicnetwork := getMultiNicCNINetwork("test-invalid-typed", cniVersion, IPVLAN_TYPE, map[string]string{})
+		multinicnetwork.Spec.MainPlugin.Mode = "bridge"
+		_, _, err := ipvlanPlugin.GetConfig(*multinicnetwork, nil)
+		Expect(err).To(HaveOccurred())
+		multinicnetwork.Spec.MainPlugin.Mode = "bridge"
+		multinicnetwork.Spec.MainPlugin.Flag = "private"
+		_, _, err = macvlanPlugin.GetConfig(*multinicnetwork, nil)
+		Expect(err).To(HaveOccurred())
+		multinicnetwork.Spec.MainPlugin.Flag = ""
+		multinicnetwork.Spec.MainPlugin.Sysctls = map[string]string{"kernel.panic": "1"}
+		_, _, err = macvlanPlugin.GetConfig(*multinicnetwork, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("aws-ipvlan main plugin", func() {
 		awsIPVlan := &AwsVpcCNIPlugin{}
 		cniType := AWS_IPVLAN_TYPE
@@ -7,8 +7,11 @@ package plugin
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+
+	multinicv1 "github.com/foundation-model-stack/multi-nic-cni/api/v1"
 )
 
 const (
@@ -30,6 +33,39 @@ func getBoolean(values map[string]string, key string) (bool, error) {
 	return false, fmt.Errorf("unset")
 }
 
+var sysctlKeyRegex = regexp.MustCompile(`^net\.[a-zA-Z0-9_.-]+$`)
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateLinkSpec validates typed macvlan/ipvlan settings of plugin spec
+func validateLinkSpec(spec multinicv1.PluginSpec, modes []string, flags []string) error {
+	if spec.Mode != "" && !contains(modes, spec.Mode) {
+		return fmt.Errorf("invalid %s mode %s, must be one of %v", spec.Type, spec.Mode, modes)
+	}
+	if spec.Flag != "" && !contains(flags, spec.Flag) {
+		if len(flags) == 0 {
+			return fmt.Errorf("flag is not supported by %s", spec.Type)
+		}
+		return fmt.Errorf("invalid %s flag %s, must be one of %v", spec.Type, spec.Flag, flags)
+	}
+	if spec.MTU != 0 && (spec.MTU < 68 || spec.MTU > 65535) {
+		return fmt.Errorf("invalid mtu %d, must be in range 68-65535", spec.MTU)
+	}
+	for key := range spec.Sysctls {
+		if !sysctlKeyRegex.MatchString(key) {
+			return fmt.Errorf("invalid sysctl %s, must be under net", key)
+		}
+	}
+	return nil
+}
+
 func ValidateResourceName(name string) string {
 	name = strings.ReplaceAll(name, ".", "")
 	name = strings.ReplaceAll(name, "-", "")
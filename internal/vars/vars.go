@@ -18,9 +18,10 @@ import (
 
 const (
 	// environment name definition
-	MaxQueueSizeKey   = "MAX_QSIZE"       // daemon pod queue size
-	TickerIntervalKey = "TICKER_INTERVAL" // synchronizer ticker interval
-	NodeNameKey       = "K8S_NODENAME"
+	MaxQueueSizeKey    = "MAX_QSIZE"       // daemon pod queue size
+	TickerIntervalKey  = "TICKER_INTERVAL" // synchronizer ticker interval
+	NodeNameKey        = "K8S_NODENAME"
+	InterfaceFilterKey = "INTERFACE_FILTER" // daemon interface filter in JSON
 
 	// common constant
 	PodStatusField                            = "status.phase"